@@ -0,0 +1,344 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+// The requests below cover parts of the PIM API that azurepag-client-go does not
+// expose yet. They reuse the client's base URL, HTTP client and token.
+
+var errRoleAssignmentNotFound = errors.New("Role assignment not found.")
+
+type RoleAssignmentSchedule struct {
+	Type          string     `json:"type"`
+	StartDateTime *time.Time `json:"startDateTime,omitempty"`
+	EndDateTime   *time.Time `json:"endDateTime,omitempty"`
+	Duration      string     `json:"duration,omitempty"`
+}
+
+type RoleAssignmentSubject struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	DisplayName string `json:"displayName"`
+}
+
+type RoleAssignment struct {
+	ID                             string                 `json:"id"`
+	ResourceID                     string                 `json:"resourceId"`
+	RoleDefinitionID               string                 `json:"roleDefinitionId"`
+	SubjectID                      string                 `json:"subjectId"`
+	AssignmentState                string                 `json:"assignmentState"`
+	StartDateTime                  *time.Time             `json:"startDateTime"`
+	EndDateTime                    *time.Time             `json:"endDateTime"`
+	MemberType                     string                 `json:"memberType"`
+	LinkedEligibleRoleAssignmentID string                 `json:"linkedEligibleRoleAssignmentId"`
+	Subject                        *RoleAssignmentSubject `json:"subject"`
+}
+
+// RoleDefinition also carries the template and resource IDs, which azurepag.RoleDefinition leaves out.
+type RoleDefinition struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	TemplateID  string `json:"templateId"`
+	ResourceID  string `json:"resourceId"`
+}
+
+type RoleDefinitionsApiResponse struct {
+	RoleDefinitions []RoleDefinition `json:"value"`
+}
+
+// RegisteredGroup is a group registered for privileged access, represented in PIM as a resource.
+// ExternalID is the object ID of the Azure AD group, ID the ID of the PIM resource.
+type RegisteredGroup struct {
+	ID              string           `json:"id"`
+	ExternalID      string           `json:"externalId"`
+	DisplayName     string           `json:"displayName"`
+	Status          string           `json:"status"`
+	RoleDefinitions []RoleDefinition `json:"roleDefinitions"`
+}
+
+type RegisteredGroupsApiResponse struct {
+	RegisteredGroups []RegisteredGroup `json:"value"`
+	NextLink         string            `json:"@odata.nextLink"`
+}
+
+type RoleAssignmentsApiResponse struct {
+	RoleAssignments []RoleAssignment `json:"value"`
+	NextLink        string           `json:"@odata.nextLink"`
+}
+
+type RoleAssignmentRequestApiRequest struct {
+	ResourceID                     string                  `json:"resourceId"`
+	RoleDefinitionID               string                  `json:"roleDefinitionId"`
+	SubjectID                      string                  `json:"subjectId"`
+	AssignmentState                string                  `json:"assignmentState"`
+	Type                           string                  `json:"type"`
+	LinkedEligibleRoleAssignmentID string                  `json:"linkedEligibleRoleAssignmentId,omitempty"`
+	Reason                         string                  `json:"reason,omitempty"`
+	TicketNumber                   string                  `json:"ticketNumber,omitempty"`
+	TicketSystem                   string                  `json:"ticketSystem,omitempty"`
+	Schedule                       *RoleAssignmentSchedule `json:"schedule,omitempty"`
+}
+
+type RoleAssignmentRequestStatusDetail struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type RoleAssignmentRequestStatus struct {
+	Status        string                              `json:"status"`
+	SubStatus     string                              `json:"subStatus"`
+	StatusDetails []RoleAssignmentRequestStatusDetail `json:"statusDetails"`
+}
+
+type RoleAssignmentRequestApiResponse struct {
+	ID              string                       `json:"id"`
+	Type            string                       `json:"type"`
+	AssignmentState string                       `json:"assignmentState"`
+	Status          *RoleAssignmentRequestStatus `json:"status"`
+	Schedule        *RoleAssignmentSchedule      `json:"schedule"`
+}
+
+func doRequest(client *azurepag.Client, req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", "Bearer", client.Token))
+	req.Header.Set("Content-Type", "application/json")
+	if client.UserAgent != "" {
+		req.Header.Set("User-Agent", client.UserAgent)
+	}
+
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	success := res.StatusCode >= 200 && res.StatusCode < 300
+	if !success {
+		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	}
+
+	return body, err
+}
+
+// getCallerObjectID reads the object ID of the signed-in principal from the oid claim of the client's token.
+func getCallerObjectID(client *Client) (string, error) {
+	parts := strings.Split(client.Token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("The API token is not a JWT, subject_id must be set explicitly.")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", err
+	}
+
+	claims := struct {
+		ObjectID string `json:"oid"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return "", err
+	}
+	if claims.ObjectID == "" {
+		return "", errors.New("The API token has no oid claim, subject_id must be set explicitly.")
+	}
+	return claims.ObjectID, nil
+}
+
+func isNotFoundError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "status: 404,")
+}
+
+func getRoleAssignment(client *Client, objectID string, subjectID string, roleDefinitionID string, assignmentState string) (*RoleAssignment, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/privilegedAccess/aadGroups/roleAssignments?$filter=(roleDefinition/resource/id%%20eq%%20%%27%s%%27)+and+(roleDefinition/id%%20eq%%20%%27%s%%27)+and+(subjectId%%20eq%%20%%27%s%%27)+and+(assignmentState%%20eq%%20%%27%s%%27)", client.BaseURL, objectID, roleDefinitionID, subjectID, assignmentState), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := doRequest(client.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := RoleAssignmentsApiResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.RoleAssignments) == 0 {
+		return nil, errRoleAssignmentNotFound
+	}
+	return &response.RoleAssignments[0], nil
+}
+
+// listRoleAssignments returns every assignment on a group with its subject expanded, following paged
+// responses. Empty roleDefinitionID or assignmentState values match any role or state.
+func listRoleAssignments(client *Client, objectID string, roleDefinitionID string, assignmentState string) ([]RoleAssignment, error) {
+	filter := fmt.Sprintf("(roleDefinition/resource/id%%20eq%%20%%27%s%%27)", objectID)
+	if roleDefinitionID != "" {
+		filter += fmt.Sprintf("+and+(roleDefinition/id%%20eq%%20%%27%s%%27)", roleDefinitionID)
+	}
+	if assignmentState != "" {
+		filter += fmt.Sprintf("+and+(assignmentState%%20eq%%20%%27%s%%27)", assignmentState)
+	}
+
+	result := []RoleAssignment{}
+	next := fmt.Sprintf("%s/privilegedAccess/aadGroups/roleAssignments?$expand=subject&$filter=%s", client.BaseURL, filter)
+	for next != "" {
+		req, err := http.NewRequest("GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := doRequest(client.Client, req)
+		if err != nil {
+			return nil, err
+		}
+
+		response := RoleAssignmentsApiResponse{}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, response.RoleAssignments...)
+		next = response.NextLink
+	}
+	return result, nil
+}
+
+func submitRoleAssignmentRequest(client *Client, request *RoleAssignmentRequestApiRequest) (*RoleAssignmentRequestApiResponse, error) {
+	rb, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/privilegedAccess/aadGroups/roleAssignmentRequests", client.BaseURL), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := doRequest(client.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := RoleAssignmentRequestApiResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func cancelRoleAssignmentRequest(client *Client, requestID string) error {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/privilegedAccess/aadGroups/roleAssignmentRequests/%s/cancel", client.BaseURL, requestID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = doRequest(client.Client, req)
+	return err
+}
+
+func getRoleAssignmentRequest(client *Client, requestID string) (*RoleAssignmentRequestApiResponse, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/privilegedAccess/aadGroups/roleAssignmentRequests/%s", client.BaseURL, requestID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := doRequest(client.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := RoleAssignmentRequestApiResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func listRoleDefinitions(client *Client, objectID string) ([]RoleDefinition, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/privilegedAccess/aadGroups/resources/%s/roleDefinitions", client.BaseURL, objectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := doRequest(client.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := RoleDefinitionsApiResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.RoleDefinitions, nil
+}
+
+// findRoleDefinition looks up a role definition by name. Unlike the client's GetRoleDefinition it returns an
+// error instead of panicking when the group has no role definitions, e.g. because it is not registered.
+func findRoleDefinition(client *Client, objectID string, roleName string) (*RoleDefinition, error) {
+	roleDefinitions, err := listRoleDefinitions(client, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range roleDefinitions {
+		if strings.EqualFold(item.DisplayName, roleName) {
+			return &roleDefinitions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Group %s has no %s role definition, it may not be registered for privileged access yet.", objectID, roleName)
+}
+
+// listRegisteredGroups returns the registered groups whose display name starts with displayNamePrefix,
+// with their role definitions expanded.
+func listRegisteredGroups(client *Client, displayNamePrefix string) ([]RegisteredGroup, error) {
+	query := "$expand=roleDefinitions"
+	if displayNamePrefix != "" {
+		query += "&$filter=" + url.QueryEscape(fmt.Sprintf("startswith(displayName,%s)", graphStringLiteral(displayNamePrefix)))
+	}
+
+	result := []RegisteredGroup{}
+	next := fmt.Sprintf("%s/privilegedAccess/aadGroups/resources?%s", client.BaseURL, query)
+	for next != "" {
+		req, err := http.NewRequest("GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := doRequest(client.Client, req)
+		if err != nil {
+			return nil, err
+		}
+
+		response := RegisteredGroupsApiResponse{}
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, response.RegisteredGroups...)
+		next = response.NextLink
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestGetCallerObjectID(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"oid":"00000000-0000-0000-0000-000000000001","tid":"tenant"}`))
+	token := "eyJhbGciOiJub25lIn0." + payload + ".signature"
+
+	objectID, err := getCallerObjectID(&Client{Client: &azurepag.Client{Token: token}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if objectID != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("unexpected object ID %s", objectID)
+	}
+
+	if _, err := getCallerObjectID(&Client{Client: &azurepag.Client{Token: "opaque"}}); err == nil {
+		t.Error("opaque token: expected an error")
+	}
+}
+
+func TestListRoleAssignmentsFollowsNextLink(t *testing.T) {
+	var server *httptest.Server
+	requests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("$skiptoken") == "" {
+			if expand := r.URL.Query().Get("$expand"); expand != "subject" {
+				t.Errorf("expected subjects to be expanded, got %q", expand)
+			}
+			fmt.Fprintf(w, `{"value":[{"id":"a","subjectId":"1"}],"@odata.nextLink":"%s/privilegedAccess/aadGroups/roleAssignments?$skiptoken=2"}`, server.URL)
+			return
+		}
+		w.Write([]byte(`{"value":[{"id":"b","subjectId":"2","subject":{"id":"2","type":"Group"}}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	roleAssignments, err := listRoleAssignments(client, "group", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if requests != 2 || len(roleAssignments) != 2 {
+		t.Fatalf("expected 2 assignments from 2 pages, got %d from %d", len(roleAssignments), requests)
+	}
+	if roleAssignments[1].Subject == nil || roleAssignments[1].Subject.Type != "Group" {
+		t.Errorf("expected the subject of the second assignment to be a group, got %+v", roleAssignments[1].Subject)
+	}
+}
+
+func TestListRegisteredGroupsFollowsNextLink(t *testing.T) {
+	var server *httptest.Server
+	requests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("$skiptoken") == "" {
+			if expand := r.URL.Query().Get("$expand"); expand != "roleDefinitions" {
+				t.Errorf("expected role definitions to be expanded, got %q", expand)
+			}
+			if filter := r.URL.Query().Get("$filter"); filter != "startswith(displayName,'PAG''s')" {
+				t.Errorf("unexpected filter %q", filter)
+			}
+			fmt.Fprintf(w, `{"value":[{"id":"a","externalId":"a","displayName":"PAG's Data","roleDefinitions":[{"id":"owner","displayName":"Owner"}]}],"@odata.nextLink":"%s/privilegedAccess/aadGroups/resources?$skiptoken=2"}`, server.URL)
+			return
+		}
+		w.Write([]byte(`{"value":[{"id":"b","externalId":"b","displayName":"PAG's Platform"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	registeredGroups, err := listRegisteredGroups(client, "PAG's")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if requests != 2 || len(registeredGroups) != 2 {
+		t.Fatalf("expected 2 groups from 2 pages, got %d from %d", len(registeredGroups), requests)
+	}
+	if len(registeredGroups[0].RoleDefinitions) != 1 || registeredGroups[1].RoleDefinitions != nil {
+		t.Errorf("expected role definitions only on the first group, got %+v", registeredGroups)
+	}
+}
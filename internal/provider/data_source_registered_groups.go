@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceRegisteredGroups() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the groups registered for privileged access in the tenant, together with their role definitions.",
+
+		ReadContext: dataSourceRegisteredGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"display_name_prefix": {
+				Description: "Only list groups whose display name starts with this value. The filter is applied by PIM.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"groups": {
+				Description: "Registered groups ordered by display name.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_id": {
+							Description: "Object ID of the Azure AD group",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"display_name": {
+							Description: "Display name of the group",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"status": {
+							Description: "Registration status reported by PIM, e.g. `Active`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"role_definition_ids": {
+							Description: "IDs of the group's role definitions keyed by role name, e.g. `Owner`.",
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRegisteredGroupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	displayNamePrefix := d.Get("display_name_prefix").(string)
+
+	registeredGroups, err := listRegisteredGroups(client, displayNamePrefix)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := []interface{}{}
+	for _, item := range filterRegisteredGroups(registeredGroups, displayNamePrefix) {
+		// Role definitions are normally expanded in the listing, fetch them only for groups that came without.
+		roleDefinitions := item.RoleDefinitions
+		if roleDefinitions == nil {
+			roleDefinitions, err = listRoleDefinitions(client, item.ID)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("Could not list the role definitions of group %s.", item.DisplayName),
+					Detail:   err.Error(),
+				})
+			}
+		}
+
+		roleDefinitionIds := map[string]interface{}{}
+		for _, roleDefinition := range roleDefinitions {
+			roleDefinitionIds[roleDefinition.DisplayName] = roleDefinition.ID
+		}
+
+		objectId := item.ExternalID
+		if objectId == "" {
+			objectId = item.ID
+		}
+
+		result = append(result, map[string]interface{}{
+			"object_id":           objectId,
+			"display_name":        item.DisplayName,
+			"status":              item.Status,
+			"role_definition_ids": roleDefinitionIds,
+		})
+	}
+
+	d.Set("groups", result)
+	d.SetId(fmt.Sprintf("registeredGroups/%s", displayNamePrefix))
+
+	return diags
+}
+
+// filterRegisteredGroups keeps the groups whose display name starts with prefix, ordered by display name.
+// PIM already filters by prefix, this only guards against a listing that ignored the filter.
+func filterRegisteredGroups(registeredGroups []RegisteredGroup, prefix string) []RegisteredGroup {
+	result := []RegisteredGroup{}
+	for _, item := range registeredGroups {
+		if strings.HasPrefix(strings.ToLower(item.DisplayName), strings.ToLower(prefix)) {
+			result = append(result, item)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].DisplayName != result[j].DisplayName {
+			return result[i].DisplayName < result[j].DisplayName
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
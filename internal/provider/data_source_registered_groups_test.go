@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestFilterRegisteredGroups(t *testing.T) {
+	registeredGroups := []RegisteredGroup{
+		{ID: "3", DisplayName: "PAG-Platform"},
+		{ID: "1", DisplayName: "pag-Data"},
+		{ID: "2", DisplayName: "Finance"},
+	}
+
+	cases := map[string][]string{
+		"":      {"2", "3", "1"},
+		"pag-":  {"3", "1"},
+		"PAG-D": {"1"},
+		"HR":    {},
+	}
+	for prefix, expected := range cases {
+		ids := []string{}
+		for _, item := range filterRegisteredGroups(registeredGroups, prefix) {
+			ids = append(ids, item.ID)
+		}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Errorf("%q: expected %v, got %v", prefix, expected, ids)
+		}
+	}
+}
+
+func TestDataSourceRegisteredGroupsReadKeepsGroupsWithoutRoleDefinitions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/roleDefinitions") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"value":[{"id":"resource","externalId":"group","displayName":"PAG-Data","status":"Active"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+	d := schema.TestResourceDataRaw(t, dataSourceRegisteredGroups().Schema, map[string]interface{}{})
+
+	diags := dataSourceRegisteredGroupsRead(context.Background(), d, client)
+	if diags.HasError() || len(diags) != 1 {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+	if objectId := d.Get("groups.0.object_id"); objectId != "group" {
+		t.Errorf("expected the group's object ID, got %v", objectId)
+	}
+}
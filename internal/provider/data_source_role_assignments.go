@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceRoleAssignments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the current eligible and active assignments on a Privileged Access Group, including inherited ones. Expired assignments are left out.",
+
+		ReadContext: dataSourceRoleAssignmentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_name": {
+				Description:  "Only list assignments of this role, either `Owner` or `Member` (case-insensitive).",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRoleName,
+			},
+			"assignment_state": {
+				Description:  "Only list assignments in this state, either `Eligible` or `Active` (case-insensitive).",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAssignmentState,
+			},
+			"role_assignments": {
+				Description: "Assignments ordered by role, state and subject.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of the role assignment",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"subject_id": {
+							Description: "Object ID of the assigned user or group",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"subject_type": {
+							Description: "Type of the subject, e.g. `User` or `Group`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"role_definition_id": {
+							Description: "ID of the assigned role definition",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"role_name": {
+							Description: "Either `Owner` or `Member`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"assignment_state": {
+							Description: "Either `Eligible` or `Active`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"start_date_time": {
+							Description: "Start of the assignment.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"end_date_time": {
+							Description: "End of the assignment, empty for permanent assignments.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"member_type": {
+							Description: "`Direct` for assignments made on this group, `Inherited` for assignments received through group membership.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"linked_eligible_role_assignment_id": {
+							Description: "ID of the eligible assignment an active assignment was activated from.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRoleAssignmentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := canonicalCase(roleNames)(d.Get("role_name").(string))
+	assignmentState := canonicalCase(assignmentStates)(d.Get("assignment_state").(string))
+
+	roleDefinitions, err := listRoleDefinitions(client, objectId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleDefinitionNames := map[string]string{}
+	roleDefinitionId := ""
+	for _, item := range roleDefinitions {
+		roleDefinitionNames[item.ID] = item.DisplayName
+		if item.DisplayName == roleName {
+			roleDefinitionId = item.ID
+		}
+	}
+	if roleName != "" && roleDefinitionId == "" {
+		return diag.Errorf("Group %s has no %s role definition.", objectId, roleName)
+	}
+
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinitionId, assignmentState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("role_assignments", flattenRoleAssignments(roleAssignments, roleDefinitionNames, time.Now()))
+	d.SetId(fmt.Sprintf("%s/%s/%s", objectId, roleName, assignmentState))
+
+	return diags
+}
+
+// flattenRoleAssignments drops expired assignments and orders the rest by role, state and subject.
+func flattenRoleAssignments(roleAssignments []RoleAssignment, roleDefinitionNames map[string]string, now time.Time) []interface{} {
+	current := make([]RoleAssignment, 0, len(roleAssignments))
+	for _, item := range roleAssignments {
+		if item.EndDateTime != nil && item.EndDateTime.Before(now) {
+			continue
+		}
+		current = append(current, item)
+	}
+
+	sort.SliceStable(current, func(i, j int) bool {
+		a, b := current[i], current[j]
+		if roleDefinitionNames[a.RoleDefinitionID] != roleDefinitionNames[b.RoleDefinitionID] {
+			return roleDefinitionNames[a.RoleDefinitionID] < roleDefinitionNames[b.RoleDefinitionID]
+		}
+		if a.AssignmentState != b.AssignmentState {
+			return a.AssignmentState < b.AssignmentState
+		}
+		if a.SubjectID != b.SubjectID {
+			return a.SubjectID < b.SubjectID
+		}
+		return a.ID < b.ID
+	})
+
+	result := make([]interface{}, 0, len(current))
+	for _, item := range current {
+		subjectType := ""
+		if item.Subject != nil {
+			subjectType = item.Subject.Type
+		}
+		result = append(result, map[string]interface{}{
+			"id":                                 item.ID,
+			"subject_id":                         item.SubjectID,
+			"subject_type":                       subjectType,
+			"role_definition_id":                 item.RoleDefinitionID,
+			"role_name":                          roleDefinitionNames[item.RoleDefinitionID],
+			"assignment_state":                   item.AssignmentState,
+			"start_date_time":                    formatDateTime(item.StartDateTime),
+			"end_date_time":                      formatDateTime(item.EndDateTime),
+			"member_type":                        item.MemberType,
+			"linked_eligible_role_assignment_id": item.LinkedEligibleRoleAssignmentID,
+		})
+	}
+	return result
+}
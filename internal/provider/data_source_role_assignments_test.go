@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlattenRoleAssignments(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	roleDefinitionNames := map[string]string{"owner": "Owner", "member": "Member"}
+	roleAssignments := []RoleAssignment{
+		{ID: "4", RoleDefinitionID: "owner", SubjectID: "b", AssignmentState: "Eligible", EndDateTime: &future},
+		{ID: "3", RoleDefinitionID: "owner", SubjectID: "a", AssignmentState: "Eligible", Subject: &RoleAssignmentSubject{Type: "User"}},
+		{ID: "2", RoleDefinitionID: "owner", SubjectID: "a", AssignmentState: "Active", LinkedEligibleRoleAssignmentID: "3", MemberType: "Direct"},
+		{ID: "1", RoleDefinitionID: "member", SubjectID: "c", AssignmentState: "Eligible", MemberType: "Inherited"},
+		{ID: "expired", RoleDefinitionID: "owner", SubjectID: "d", AssignmentState: "Active", EndDateTime: &past},
+	}
+
+	result := flattenRoleAssignments(roleAssignments, roleDefinitionNames, now)
+
+	ids := []string{}
+	for _, item := range result {
+		ids = append(ids, item.(map[string]interface{})["id"].(string))
+	}
+	if expected := []string{"1", "2", "3", "4"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected assignments %v, got %v", expected, ids)
+	}
+
+	active := result[1].(map[string]interface{})
+	if active["role_name"] != "Owner" || active["linked_eligible_role_assignment_id"] != "3" || active["member_type"] != "Direct" {
+		t.Errorf("unexpected active assignment %v", active)
+	}
+	if subjectType := result[2].(map[string]interface{})["subject_type"]; subjectType != "User" {
+		t.Errorf("expected subject type User, got %v", subjectType)
+	}
+}
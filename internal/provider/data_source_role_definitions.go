@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceRoleDefinitions() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists the role definitions of a group registered for privileged access.",
+
+		ReadContext: dataSourceRoleDefinitionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_definitions": {
+				Description: "Role definitions of the group, usually `Owner` and `Member`.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of the role definition",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"display_name": {
+							Description: "Name of the role, e.g. `Owner`.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"template_id": {
+							Description: "ID of the template the role definition was created from.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"resource_id": {
+							Description: "ID of the PIM resource representing the group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRoleDefinitionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+
+	roleDefinitions, err := listRoleDefinitions(client, objectId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("role_definitions", flattenRoleDefinitions(roleDefinitions))
+	d.SetId(objectId)
+
+	return diags
+}
+
+func flattenRoleDefinitions(roleDefinitions []RoleDefinition) []interface{} {
+	result := make([]interface{}, 0, len(roleDefinitions))
+	for _, item := range roleDefinitions {
+		result = append(result, map[string]interface{}{
+			"id":           item.ID,
+			"display_name": item.DisplayName,
+			"template_id":  item.TemplateID,
+			"resource_id":  item.ResourceID,
+		})
+	}
+	return result
+}
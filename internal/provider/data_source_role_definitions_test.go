@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestListRoleDefinitions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if expected := "/privilegedAccess/aadGroups/resources/group/roleDefinitions"; r.URL.Path != expected {
+			t.Errorf("expected a request to %s, got %s", expected, r.URL.Path)
+		}
+		w.Write([]byte(`{"value":[{"id":"owner","displayName":"Owner","templateId":"owner-template","resourceId":"group"},{"id":"member","displayName":"Member","templateId":"member-template","resourceId":"group"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	roleDefinitions, err := listRoleDefinitions(client, "group")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"id": "owner", "display_name": "Owner", "template_id": "owner-template", "resource_id": "group"},
+		map[string]interface{}{"id": "member", "display_name": "Member", "template_id": "member-template", "resource_id": "group"},
+	}
+	if actual := flattenRoleDefinitions(roleDefinitions); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
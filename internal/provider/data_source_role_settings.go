@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceRoleSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the PIM role settings of a Privileged Access Group's `Owner` or `Member` role, including every notification and the raw policy document.",
+
+		ReadContext: dataSourceRoleSettingsRead,
+
+		Schema: map[string]*schema.Schema{
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_name": {
+				Description:  "Group role to read, either `Owner` or `Member` (case-insensitive).",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRoleName,
+			},
+			"role_definition_id": {
+				Description: "ID of the role definition",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"activation": {
+				Description: "Rules applied when an eligible principal activates the role.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_duration_mins":      {Type: schema.TypeInt, Computed: true},
+						"max_duration":           {Type: schema.TypeString, Computed: true},
+						"require_mfa":            {Type: schema.TypeBool, Computed: true},
+						"authentication_context": {Type: schema.TypeString, Computed: true},
+						"require_justification":  {Type: schema.TypeBool, Computed: true},
+						"require_ticket_info":    {Type: schema.TypeBool, Computed: true},
+						"require_approval":       {Type: schema.TypeBool, Computed: true},
+						"approver": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"object_id": {Type: schema.TypeString, Computed: true},
+									"type":      {Type: schema.TypeString, Computed: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			"eligible_assignment": {
+				Description: "Rules applied when an administrator makes an eligible assignment.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_permanent":   {Type: schema.TypeBool, Computed: true},
+						"max_duration_mins": {Type: schema.TypeInt, Computed: true},
+						"max_duration":      {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+			"active_assignment": {
+				Description: "Rules applied when an administrator makes an active assignment.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_permanent":       {Type: schema.TypeBool, Computed: true},
+						"max_duration_mins":     {Type: schema.TypeInt, Computed: true},
+						"max_duration":          {Type: schema.TypeString, Computed: true},
+						"require_mfa":           {Type: schema.TypeBool, Computed: true},
+						"require_justification": {Type: schema.TypeBool, Computed: true},
+					},
+				},
+			},
+			"notification": {
+				Description: "Email notifications sent for eligible assignments, active assignments and activations.",
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":                  {Type: schema.TypeString, Computed: true},
+						"recipient_type":        {Type: schema.TypeString, Computed: true},
+						"default_recipients":    {Type: schema.TypeBool, Computed: true},
+						"additional_recipients": {Type: schema.TypeSet, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"critical_only":         {Type: schema.TypeBool, Computed: true},
+					},
+				},
+			},
+			"raw_settings_json": {
+				Description: "Role settings as read from PIM, including rules the provider does not model.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceRoleSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := canonicalCase(roleNames)(d.Get("role_name").(string))
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleSettings, err := client.GetRoleSettings(objectId, roleDefinition.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleSettingsOptions, err := getRoleSettingsOptions(roleSettings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rawSettings, err := json.Marshal(roleSettings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("activation", flattenRoleSettingsActivation(roleSettingsOptions, formatDurationMinutes(roleSettingsOptions.MaxActivationTimeMins)))
+	d.Set("eligible_assignment", flattenRoleSettingsEligibleAssignment(roleSettingsOptions, formatDurationMinutes(roleSettingsOptions.MaxEligibleAssignmentTimeMins)))
+	d.Set("active_assignment", flattenRoleSettingsActiveAssignment(roleSettingsOptions, formatDurationMinutes(roleSettingsOptions.MaxActiveAssignmentTimeMins)))
+	d.Set("notification", flattenRoleSettingsNotifications(roleSettingsOptions.Notifications))
+	d.Set("raw_settings_json", string(rawSettings))
+	d.Set("role_definition_id", roleDefinition.ID)
+	d.SetId(roleSettings.ID)
+
+	return diags
+}
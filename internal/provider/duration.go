@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Only the fixed-length ISO 8601 designators are supported, years and months have no exact length.
+var iso8601DurationRegexp = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func parseISO8601Duration(v string) (time.Duration, error) {
+	matches := iso8601DurationRegexp.FindStringSubmatch(v)
+	if matches == nil || v == "P" || v[len(v)-1] == 'T' {
+		return 0, fmt.Errorf("%q is not an ISO 8601 duration such as P30D or PT8H", v)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+	var result time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, err
+		}
+		result += time.Duration(n) * unit
+	}
+
+	return result, nil
+}
+
+func validateISO8601Duration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if _, err := parseISO8601Duration(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+// parseDuration accepts either an ISO 8601 duration such as P365D or a Go duration such as 8h.
+func parseDuration(v string) (time.Duration, error) {
+	if strings.HasPrefix(v, "P") {
+		return parseISO8601Duration(v)
+	}
+
+	result, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither an ISO 8601 duration such as P365D nor a Go duration such as 8h", v)
+	}
+	if result < 0 {
+		return 0, fmt.Errorf("%q is negative", v)
+	}
+	return result, nil
+}
+
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if _, err := parseDuration(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+// formatDurationMinutes formats a number of minutes as a Go duration such as 8h0m0s.
+func formatDurationMinutes(minutes int) string {
+	return (time.Duration(minutes) * time.Minute).String()
+}
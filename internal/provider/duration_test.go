@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISO8601Duration(t *testing.T) {
+	valid := map[string]time.Duration{
+		"P365D":     365 * 24 * time.Hour,
+		"P2W":       14 * 24 * time.Hour,
+		"PT8H":      8 * time.Hour,
+		"PT30M":     30 * time.Minute,
+		"P1DT12H":   36 * time.Hour,
+		"PT1H30M5S": time.Hour + 30*time.Minute + 5*time.Second,
+	}
+	for v, expected := range valid {
+		actual, err := parseISO8601Duration(v)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", v, err)
+		} else if actual != expected {
+			t.Errorf("%s: expected %s, got %s", v, expected, actual)
+		}
+	}
+
+	invalid := []string{"", "P", "PT", "P1Y", "P1M", "8h", "P1H", "PT-1H"}
+	for _, v := range invalid {
+		if _, err := parseISO8601Duration(v); err == nil {
+			t.Errorf("%s: expected an error", v)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	valid := map[string]time.Duration{
+		"P365D":  365 * 24 * time.Hour,
+		"PT8H":   8 * time.Hour,
+		"8h":     8 * time.Hour,
+		"90m":    90 * time.Minute,
+		"1h30m":  90 * time.Minute,
+		"8760h":  365 * 24 * time.Hour,
+		"P1DT1H": 25 * time.Hour,
+	}
+	for v, expected := range valid {
+		actual, err := parseDuration(v)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", v, err)
+		} else if actual != expected {
+			t.Errorf("%s: expected %s, got %s", v, expected, actual)
+		}
+	}
+
+	invalid := []string{"", "P", "P1Y", "8 hours", "-8h", "365d"}
+	for _, v := range invalid {
+		if _, err := parseDuration(v); err == nil {
+			t.Errorf("%s: expected an error", v)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const GraphBaseURL string = "https://graph.microsoft.com/v1.0"
+
+type GraphDirectoryObject struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+type GraphDirectoryObjectsApiResponse struct {
+	DirectoryObjects []GraphDirectoryObject `json:"value"`
+}
+
+// resolveUserObjectID finds the single user whose user principal name or mail address matches value.
+func resolveUserObjectID(client *Client, value string) (string, error) {
+	literal := graphStringLiteral(value)
+	filter := fmt.Sprintf("userPrincipalName eq %s or mail eq %s", literal, literal)
+	return resolveDirectoryObjectID(client, "users", filter, fmt.Sprintf("user %q", value))
+}
+
+// resolveGroupObjectID finds the single group with the given display name.
+func resolveGroupObjectID(client *Client, displayName string) (string, error) {
+	filter := fmt.Sprintf("displayName eq %s", graphStringLiteral(displayName))
+	return resolveDirectoryObjectID(client, "groups", filter, fmt.Sprintf("group %q", displayName))
+}
+
+func resolveDirectoryObjectID(client *Client, collection string, filter string, description string) (string, error) {
+	if client.Graph == nil {
+		return "", fmt.Errorf("Resolving %s requires graph_token to be set in the provider configuration.", description)
+	}
+
+	query := url.Values{}
+	query.Set("$filter", filter)
+	query.Set("$select", "id,displayName")
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s?%s", client.Graph.BaseURL, collection, query.Encode()), nil)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := doRequest(client.Graph, req)
+	if err != nil {
+		return "", err
+	}
+
+	response := GraphDirectoryObjectsApiResponse{}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(response.DirectoryObjects) {
+	case 0:
+		return "", fmt.Errorf("No %s found in Microsoft Graph.", description)
+	case 1:
+		return response.DirectoryObjects[0].ID, nil
+	default:
+		return "", fmt.Errorf("Found %d matches for %s in Microsoft Graph, use subject_id instead.", len(response.DirectoryObjects), description)
+	}
+}
+
+// graphStringLiteral quotes a value for an OData filter, doubling embedded single quotes.
+func graphStringLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
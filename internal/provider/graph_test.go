@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestResolveUserObjectID(t *testing.T) {
+	var filter string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("$filter")
+		w.Write([]byte(`{"value":[{"id":"00000000-0000-0000-0000-000000000001","displayName":"O'Brien"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Graph: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	objectID, err := resolveUserObjectID(client, "o'brien@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if objectID != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("unexpected object ID %s", objectID)
+	}
+	if expected := "userPrincipalName eq 'o''brien@example.com' or mail eq 'o''brien@example.com'"; filter != expected {
+		t.Errorf("expected filter %q, got %q", expected, filter)
+	}
+
+	if _, err := resolveUserObjectID(&Client{}, "user@example.com"); err == nil {
+		t.Error("missing graph_token: expected an error")
+	}
+}
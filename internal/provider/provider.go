@@ -24,6 +24,13 @@ func init() {
 	// }
 }
 
+// Client is passed to resources as provider meta. It embeds the PIM API client, Graph is only
+// set when a Microsoft Graph token is configured.
+type Client struct {
+	*azurepag.Client
+	Graph *azurepag.Client
+}
+
 func New(version string) func() *schema.Provider {
 	return func() *schema.Provider {
 		p := &schema.Provider{
@@ -33,12 +40,28 @@ func New(version string) func() *schema.Provider {
 					Required:    true,
 					DefaultFunc: schema.EnvDefaultFunc("AZUREPAG_TOKEN", nil),
 				},
+				"graph_token": &schema.Schema{
+					Description: "Microsoft Graph access token, needed to resolve subjects by user principal name or group name.",
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("AZUREPAG_GRAPH_TOKEN", nil),
+				},
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"azurepag_registration":            resourceRegistration(),
+				"azurepag_role_activation":         resourceRoleActivation(),
 				"azurepag_role_assignment_request": resourceRoleAssignmentRequest(),
+				"azurepag_role_assignment_set":     resourceRoleAssignmentSet(),
+				"azurepag_role_assignments":        resourceRoleAssignments(),
 				"azurepag_role_settings":           resourceRoleSettings(),
 			},
+			DataSourcesMap: map[string]*schema.Resource{
+				"azurepag_registered_groups": dataSourceRegisteredGroups(),
+				"azurepag_role_assignments":  dataSourceRoleAssignments(),
+				"azurepag_role_definitions":  dataSourceRoleDefinitions(),
+				"azurepag_role_settings":     dataSourceRoleSettings(),
+			},
 		}
 
 		p.ConfigureContextFunc = configure(version, p)
@@ -55,8 +78,16 @@ func configure(version string, p *schema.Provider) func(context.Context, *schema
 
 		if token != "" {
 			userAgent := p.UserAgent("terraform-provider-azurepag", version)
-			client := azurepag.NewClient(&token, &userAgent)
-			return client, diags
+			client := Client{
+				Client: azurepag.NewClient(&token, &userAgent),
+			}
+
+			if graphToken := d.Get("graph_token").(string); graphToken != "" {
+				client.Graph = azurepag.NewClient(&graphToken, &userAgent)
+				client.Graph.BaseURL = GraphBaseURL
+			}
+
+			return &client, diags
 		} else {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Error,
@@ -6,7 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/oskarm93/azurepag-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceRegistration() *schema.Resource {
@@ -19,10 +19,11 @@ func resourceRegistration() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"object_id": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
 			},
 		},
 	}
@@ -30,7 +31,7 @@ func resourceRegistration() *schema.Resource {
 
 func resourceRegistrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
 
@@ -0,0 +1,350 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRoleActivation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Activates an eligible `Owner` or `Member` role on a Privileged Access Group for the signed-in principal. Destroying the resource deactivates the role early. Once the activation expires the resource is removed from state, so the next apply activates the role again.",
+
+		CreateContext: resourceRoleActivationCreate,
+		ReadContext:   resourceRoleActivationRead,
+		DeleteContext: resourceRoleActivationDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"role_definition_id": {
+				Description: "ID of the activated role definition",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"subject_id": {
+				Description:  "Object ID of the principal activating the role. Defaults to the `oid` claim of the provider token.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_name": {
+				Description:      "Group role to activate, either `Owner` or `Member` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateRoleName,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(roleNames),
+			},
+			"duration": {
+				Description:  "ISO 8601 duration of the activation, e.g. `PT8H`. It must not exceed the role's maximum activation time.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateISO8601Duration,
+			},
+			"justification": {
+				Description: "Reason recorded with the activation and deactivation requests.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"ticket_number": {
+				Description: "Ticket number recorded with the activation and deactivation requests.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"ticket_system": {
+				Description: "Name of the ticketing system `ticket_number` belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"assignment_start_date_time": {
+				Description: "Start of the activation as scheduled by PIM.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"assignment_end_date_time": {
+				Description: "End of the activation as scheduled by PIM.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"eligible_role_assignment_id": {
+				Description: "ID of the eligible assignment that was activated. Only the activation linked to it is tracked, other active assignments of the subject are ignored.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"request_id": {
+				Description: "ID of the PIM activation request.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "Status of the activation request, e.g. `Closed`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"sub_status": {
+				Description: "Detailed status of the activation request, e.g. `Provisioned` or `PendingApproval`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"schedule_start": {
+				Description: "Start of the schedule recorded on the activation request.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"schedule_end": {
+				Description: "End of the schedule recorded on the activation request.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceRoleActivationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+
+	subjectId := d.Get("subject_id").(string)
+	if subjectId == "" {
+		callerObjectId, err := getCallerObjectID(client)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		subjectId = callerObjectId
+	}
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	eligibleAssignment, err := getRoleAssignment(client, objectId, subjectId, roleDefinition.ID, "Eligible")
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		return diag.Errorf("%s has no eligible %s assignment on group %s to activate.", subjectId, roleName, objectId)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	now := time.Now().UTC()
+	request, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+		ResourceID:                     objectId,
+		RoleDefinitionID:               roleDefinition.ID,
+		SubjectID:                      subjectId,
+		AssignmentState:                "Active",
+		Type:                           "UserAdd",
+		LinkedEligibleRoleAssignmentID: eligibleAssignment.ID,
+		Reason:                         d.Get("justification").(string),
+		TicketNumber:                   d.Get("ticket_number").(string),
+		TicketSystem:                   d.Get("ticket_system").(string),
+		Schedule: &RoleAssignmentSchedule{
+			Type:          "Once",
+			StartDateTime: &now,
+			Duration:      d.Get("duration").(string),
+		},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("subject_id", subjectId)
+	d.Set("eligible_role_assignment_id", eligibleAssignment.ID)
+	d.Set("request_id", request.ID)
+	d.SetId(request.ID)
+
+	_, diags := waitForRoleAssignmentRequest(ctx, client, request, d.Timeout(schema.TimeoutCreate))
+	if diags.HasError() {
+		d.SetId("")
+		return diags
+	}
+
+	return append(diags, resourceRoleActivationRead(ctx, d, meta)...)
+}
+
+func resourceRoleActivationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	subjectId := d.Get("subject_id").(string)
+	roleName := d.Get("role_name").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	eligibleAssignmentId, err := getActivatedEligibleAssignmentID(client, d, roleDefinition.ID)
+	if errors.Is(err, errRoleAssignmentNotFound) && !d.IsNewResource() {
+		log.Printf("[WARN] %s of group %s is no longer eligible for %s, removing the activation from state", subjectId, objectId, roleName)
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("eligible_role_assignment_id", eligibleAssignmentId)
+
+	roleAssignment, err := getActivatedRoleAssignment(client, objectId, subjectId, roleDefinition.ID, eligibleAssignmentId)
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		request, requestErr := getPendingRoleAssignmentRequest(client, d.Get("request_id").(string))
+		if requestErr != nil {
+			return diag.FromErr(requestErr)
+		}
+		if request != nil {
+			log.Printf("[INFO] %s activation of %s on group %s is waiting for request %s, which is %s", roleName, subjectId, objectId, request.ID, request.Status.SubStatus)
+			setRoleAssignmentRequestStatus(d, request)
+			return diags
+		}
+	}
+	if errors.Is(err, errRoleAssignmentNotFound) && !d.IsNewResource() {
+		log.Printf("[WARN] %s activation of %s on group %s no longer exists, removing from state", roleName, subjectId, objectId)
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if roleAssignment.EndDateTime != nil && roleAssignment.EndDateTime.Before(time.Now()) && !d.IsNewResource() {
+		log.Printf("[WARN] %s activation of %s on group %s expired at %s, removing from state", roleName, subjectId, objectId, formatDateTime(roleAssignment.EndDateTime))
+		d.SetId("")
+		return diags
+	}
+
+	if requestId := d.Get("request_id").(string); requestId != "" {
+		request, err := getRoleAssignmentRequest(client, requestId)
+		if isNotFoundError(err) {
+			log.Printf("[WARN] Role activation request %s no longer exists, keeping its last known status", requestId)
+		} else if err != nil {
+			return diag.FromErr(err)
+		} else {
+			setRoleAssignmentRequestStatus(d, request)
+		}
+	}
+
+	d.Set("assignment_start_date_time", formatDateTime(roleAssignment.StartDateTime))
+	d.Set("assignment_end_date_time", formatDateTime(roleAssignment.EndDateTime))
+	d.Set("role_definition_id", roleDefinition.ID)
+	d.SetId(roleAssignment.ID)
+
+	return diags
+}
+
+func resourceRoleActivationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	subjectId := d.Get("subject_id").(string)
+	roleName := d.Get("role_name").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	eligibleAssignmentId, err := getActivatedEligibleAssignmentID(client, d, roleDefinition.ID)
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Nothing to deactivate once the activation has expired.
+	roleAssignment, err := getActivatedRoleAssignment(client, objectId, subjectId, roleDefinition.ID, eligibleAssignmentId)
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if roleAssignment.EndDateTime != nil && roleAssignment.EndDateTime.Before(time.Now()) {
+		d.SetId("")
+		return diags
+	}
+
+	_, err = submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+		ResourceID:                     objectId,
+		RoleDefinitionID:               roleDefinition.ID,
+		SubjectID:                      subjectId,
+		AssignmentState:                "Active",
+		Type:                           "UserRemove",
+		LinkedEligibleRoleAssignmentID: eligibleAssignmentId,
+		Reason:                         d.Get("justification").(string),
+		TicketNumber:                   d.Get("ticket_number").(string),
+		TicketSystem:                   d.Get("ticket_system").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// getActivatedEligibleAssignmentID returns the eligible assignment the activation was made from. State written
+// before it was recorded falls back to the subject's current eligible assignment.
+func getActivatedEligibleAssignmentID(client *Client, d *schema.ResourceData, roleDefinitionId string) (string, error) {
+	if eligibleAssignmentId := d.Get("eligible_role_assignment_id").(string); eligibleAssignmentId != "" {
+		return eligibleAssignmentId, nil
+	}
+
+	eligibleAssignment, err := getRoleAssignment(client, d.Get("object_id").(string), d.Get("subject_id").(string), roleDefinitionId, "Eligible")
+	if err != nil {
+		return "", err
+	}
+	return eligibleAssignment.ID, nil
+}
+
+// getActivatedRoleAssignment finds the active assignment created by activating the given eligible assignment,
+// so a direct active assignment of the same subject is never mistaken for it.
+func getActivatedRoleAssignment(client *Client, objectId string, subjectId string, roleDefinitionId string, eligibleAssignmentId string) (*RoleAssignment, error) {
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinitionId, "Active")
+	if err != nil {
+		return nil, err
+	}
+
+	var result *RoleAssignment
+	for i, item := range roleAssignments {
+		if item.SubjectID != subjectId || item.LinkedEligibleRoleAssignmentID != eligibleAssignmentId {
+			continue
+		}
+		// Prefer an activation that has not expired over one PIM still lists.
+		if result == nil || item.EndDateTime == nil || (result.EndDateTime != nil && item.EndDateTime.After(*result.EndDateTime)) {
+			result = &roleAssignments[i]
+		}
+	}
+	if result == nil {
+		return nil, errRoleAssignmentNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestGetActivatedRoleAssignment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":[
+			{"id":"direct","subjectId":"user","assignmentState":"Active","memberType":"Direct"},
+			{"id":"other","subjectId":"someone","assignmentState":"Active","linkedEligibleRoleAssignmentId":"eligible"},
+			{"id":"expired","subjectId":"user","assignmentState":"Active","linkedEligibleRoleAssignmentId":"eligible","endDateTime":"2023-05-01T12:00:00Z"},
+			{"id":"activation","subjectId":"user","assignmentState":"Active","linkedEligibleRoleAssignmentId":"eligible","endDateTime":"2023-05-02T12:00:00Z"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	roleAssignment, err := getActivatedRoleAssignment(client, "group", "user", "definition", "eligible")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if roleAssignment.ID != "activation" {
+		t.Errorf("expected the latest activation, got %s", roleAssignment.ID)
+	}
+
+	if _, err := getActivatedRoleAssignment(client, "group", "user", "definition", "another"); !errors.Is(err, errRoleAssignmentNotFound) {
+		t.Errorf("expected the direct assignment to be ignored, got %v", err)
+	}
+}
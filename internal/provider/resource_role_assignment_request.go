@@ -2,20 +2,64 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/oskarm93/azurepag-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// PIM only accepts AdminExtend for assignments that expire within this window.
+const roleAssignmentExtendWindow = 14 * 24 * time.Hour
+
+// Request sub-statuses PIM reports while a request is still being evaluated, approved or provisioned.
+var roleAssignmentRequestPendingSubStatuses = []string{
+	"Accepted",
+	"PendingEvaluation",
+	"Granted",
+	"PendingApproval",
+	"PendingApprovalProvisioning",
+	"PendingAdminDecision",
+	"AdminApproved",
+	"PendingProvisioning",
+	"ProvisioningStarted",
+	"PendingScheduleCreation",
+}
+
+// roleAssignmentRequestPollInterval is how long to wait between checks of a pending request.
+var roleAssignmentRequestPollInterval = 5 * time.Second
+
+// Request sub-statuses after which the assignment will never be provisioned.
+var roleAssignmentRequestFailedSubStatuses = []string{
+	"Denied",
+	"AdminDenied",
+	"Canceled",
+	"Failed",
+	"FailedAsResourceIsLocked",
+	"PolicyEvaluationFailed",
+	"TimedOut",
+	"Revoked",
+}
+
 func resourceRoleAssignmentRequest() *schema.Resource {
 	return &schema.Resource{
 		Description: "TODO",
 
 		CreateContext: resourceRoleAssignmentRequestCreate,
 		ReadContext:   resourceRoleAssignmentRequestRead,
+		UpdateContext: resourceRoleAssignmentRequestUpdate,
 		DeleteContext: resourceRoleAssignmentRequestDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"role_definition_id": {
 				Description: "Object ID of the Azure AD group",
@@ -23,60 +67,183 @@ func resourceRoleAssignmentRequest() *schema.Resource {
 				Computed:    true,
 			},
 			"object_id": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
 			},
 			"subject_id": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:  "Object ID of the user or group receiving the assignment. Computed when the subject is given by name.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+				ExactlyOneOf: []string{"subject_id", "subject_user_principal_name", "subject_group_name"},
+			},
+			"subject_user_principal_name": {
+				Description:  "User principal name or mail address of the user receiving the assignment, resolved through Microsoft Graph.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ExactlyOneOf: []string{"subject_id", "subject_user_principal_name", "subject_group_name"},
+			},
+			"subject_group_name": {
+				Description:  "Display name of the group receiving the assignment, resolved through Microsoft Graph. It must match exactly one group.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				ExactlyOneOf: []string{"subject_id", "subject_user_principal_name", "subject_group_name"},
 			},
 			"role_name": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:      "Group role to assign, either `Owner` or `Member` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateRoleName,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(roleNames),
 			},
 			"assignment_state": {
-				Description: "Object ID of the Azure AD group",
+				Description:      "Either `Eligible` or `Active` (case-insensitive). Changing it converts the assignment in place.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validateAssignmentState,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(assignmentStates),
+			},
+			"start_date_time": {
+				Description:  "RFC3339 timestamp at which the assignment starts. Defaults to the time the request is submitted.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"end_date_time": {
+				Description:   "RFC3339 timestamp at which the assignment expires. Changing it updates, extends or renews the assignment in place. Omit both this and `duration` for a permanent assignment.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"duration"},
+			},
+			"duration": {
+				Description:   "ISO 8601 duration of the assignment counted from its start, e.g. `P30D` or `PT8H`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateISO8601Duration,
+				ConflictsWith: []string{"end_date_time"},
+			},
+			"justification": {
+				Description: "Reason recorded with every request PIM receives for this assignment, including its removal.",
 				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Optional:    true,
+			},
+			"ticket_number": {
+				Description: "Ticket number recorded with every request PIM receives for this assignment.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ticket_system": {
+				Description: "Name of the ticketing system `ticket_number` belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"assignment_start_date_time": {
+				Description: "Start of the assignment as scheduled by PIM.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"assignment_end_date_time": {
+				Description: "End of the assignment as scheduled by PIM. Empty for permanent assignments.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"request_id": {
+				Description: "ID of the last PIM request submitted for this assignment.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"status": {
+				Description: "Status of the last PIM request, e.g. `Closed`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"sub_status": {
+				Description: "Detailed status of the last PIM request, e.g. `Provisioned` or `PendingApproval`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"schedule_start": {
+				Description: "Start of the schedule recorded on the last PIM request.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"schedule_end": {
+				Description: "End of the schedule recorded on the last PIM request. Empty for permanent assignments.",
+				Type:        schema.TypeString,
+				Computed:    true,
 			},
 		},
 	}
 }
 
 func resourceRoleAssignmentRequestCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
-	subjectId := d.Get("subject_id").(string)
 	assignmentState := d.Get("assignment_state").(string)
 	roleName := d.Get("role_name").(string)
 
+	subjectId, err := getRoleAssignmentSubjectId(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("subject_id", subjectId)
+
 	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	_, err = client.CreateRoleAssignmentRequest(objectId, subjectId, roleDefinition.ID, assignmentState)
+	schedule := getRoleAssignmentSchedule(d, nil)
+	err = validateRoleAssignmentSchedule(client, objectId, roleDefinition.ID, assignmentState, schedule)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	resourceRoleAssignmentRequestRead(ctx, d, meta)
+	request, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+		ResourceID:       objectId,
+		RoleDefinitionID: roleDefinition.ID,
+		SubjectID:        subjectId,
+		AssignmentState:  assignmentState,
+		Type:             "AdminAdd",
+		Reason:           d.Get("justification").(string),
+		TicketNumber:     d.Get("ticket_number").(string),
+		TicketSystem:     d.Get("ticket_system").(string),
+		Schedule:         schedule,
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	return diags
+	// Track the request before waiting, so one that is still pending is not submitted again by the next apply.
+	d.Set("request_id", request.ID)
+	d.SetId(request.ID)
+
+	_, diags := waitForRoleAssignmentRequest(ctx, client, request, d.Timeout(schema.TimeoutCreate))
+	if diags.HasError() {
+		d.SetId("")
+		return diags
+	}
+
+	return append(diags, resourceRoleAssignmentRequestRead(ctx, d, meta)...)
 }
 
 func resourceRoleAssignmentRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
 	subjectId := d.Get("subject_id").(string)
@@ -88,20 +255,169 @@ func resourceRoleAssignmentRequestRead(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	roleAssignmentRequest, err := client.GetRoleAssignmentRequest(objectId, subjectId, roleDefinition.ID, assignmentState)
+	roleAssignment, err := getRoleAssignment(client, objectId, subjectId, roleDefinition.ID, assignmentState)
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		request, requestErr := getPendingRoleAssignmentRequest(client, d.Get("request_id").(string))
+		if requestErr != nil {
+			return diag.FromErr(requestErr)
+		}
+		if request != nil {
+			log.Printf("[INFO] %s role assignment of %s to %s on group %s is waiting for request %s, which is %s", assignmentState, roleName, subjectId, objectId, request.ID, request.Status.SubStatus)
+			setRoleAssignmentRequestStatus(d, request)
+			d.Set("role_definition_id", roleDefinition.ID)
+			return diags
+		}
+	}
+	if errors.Is(err, errRoleAssignmentNotFound) && !d.IsNewResource() {
+		log.Printf("[WARN] %s role assignment of %s to %s on group %s no longer exists, removing from state", assignmentState, roleName, subjectId, objectId)
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if roleAssignment.EndDateTime != nil && roleAssignment.EndDateTime.Before(time.Now()) && !d.IsNewResource() {
+		log.Printf("[WARN] %s role assignment of %s to %s on group %s expired at %s, removing from state", assignmentState, roleName, subjectId, objectId, formatDateTime(roleAssignment.EndDateTime))
+		d.SetId("")
+		return diags
+	}
+
+	if requestId := d.Get("request_id").(string); requestId != "" {
+		request, err := getRoleAssignmentRequest(client, requestId)
+		if isNotFoundError(err) {
+			log.Printf("[WARN] Role assignment request %s no longer exists, keeping its last known status", requestId)
+		} else if err != nil {
+			return diag.FromErr(err)
+		} else {
+			setRoleAssignmentRequestStatus(d, request)
+		}
+	}
+
+	d.Set("assignment_start_date_time", formatDateTime(roleAssignment.StartDateTime))
+	d.Set("assignment_end_date_time", formatDateTime(roleAssignment.EndDateTime))
 	d.Set("role_definition_id", roleDefinition.ID)
-	d.SetId(roleAssignmentRequest.ID)
+	d.SetId(roleAssignment.ID)
 
 	return diags
 }
 
+func resourceRoleAssignmentRequestUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	subjectId := d.Get("subject_id").(string)
+	roleName := d.Get("role_name").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("assignment_state") {
+		oldState, newState := d.GetChange("assignment_state")
+
+		schedule := getRoleAssignmentSchedule(d, nil)
+		err = validateRoleAssignmentSchedule(client, objectId, roleDefinition.ID, newState.(string), schedule)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// Add the new assignment before removing the old one so the subject never loses access.
+		request, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+			ResourceID:       objectId,
+			RoleDefinitionID: roleDefinition.ID,
+			SubjectID:        subjectId,
+			AssignmentState:  newState.(string),
+			Type:             "AdminAdd",
+			Reason:           d.Get("justification").(string),
+			TicketNumber:     d.Get("ticket_number").(string),
+			TicketSystem:     d.Get("ticket_system").(string),
+			Schedule:         schedule,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.Set("request_id", request.ID)
+
+		request, diags := waitForRoleAssignmentRequest(ctx, client, request, d.Timeout(schema.TimeoutUpdate))
+		if diags.HasError() {
+			return diags
+		}
+		if isRoleAssignmentRequestPending(request) {
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The %s assignment was kept because the %s assignment is not provisioned yet.", oldState, newState),
+				Detail:   "Remove it in PIM once the pending request has been fulfilled.",
+			})
+		}
+
+		removeRequest, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+			ResourceID:       objectId,
+			RoleDefinitionID: roleDefinition.ID,
+			SubjectID:        subjectId,
+			AssignmentState:  oldState.(string),
+			Type:             "AdminRemove",
+			Reason:           d.Get("justification").(string),
+			TicketNumber:     d.Get("ticket_number").(string),
+			TicketSystem:     d.Get("ticket_system").(string),
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, removeDiags := waitForRoleAssignmentRequest(ctx, client, removeRequest, d.Timeout(schema.TimeoutUpdate))
+		diags = append(diags, removeDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		return append(diags, resourceRoleAssignmentRequestRead(ctx, d, meta)...)
+	} else if d.HasChanges("start_date_time", "end_date_time", "duration") {
+		assignmentState := d.Get("assignment_state").(string)
+
+		roleAssignment, err := getRoleAssignment(client, objectId, subjectId, roleDefinition.ID, assignmentState)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		schedule := getRoleAssignmentSchedule(d, roleAssignment)
+		err = validateRoleAssignmentSchedule(client, objectId, roleDefinition.ID, assignmentState, schedule)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		request, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+			ResourceID:       objectId,
+			RoleDefinitionID: roleDefinition.ID,
+			SubjectID:        subjectId,
+			AssignmentState:  assignmentState,
+			Type:             getRoleAssignmentUpdateType(roleAssignment, schedule.EndDateTime, time.Now()),
+			Reason:           d.Get("justification").(string),
+			TicketNumber:     d.Get("ticket_number").(string),
+			TicketSystem:     d.Get("ticket_system").(string),
+			Schedule:         schedule,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.Set("request_id", request.ID)
+
+		_, diags := waitForRoleAssignmentRequest(ctx, client, request, d.Timeout(schema.TimeoutUpdate))
+		if diags.HasError() {
+			return diags
+		}
+
+		return append(diags, resourceRoleAssignmentRequestRead(ctx, d, meta)...)
+	}
+
+	return resourceRoleAssignmentRequestRead(ctx, d, meta)
+}
+
 func resourceRoleAssignmentRequestDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
 	subjectId := d.Get("subject_id").(string)
@@ -113,12 +429,248 @@ func resourceRoleAssignmentRequestDelete(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	err = client.DeleteRoleAssignmentRequest(objectId, subjectId, roleDefinition.ID, assignmentState)
+	// An assignment that is still waiting for its request, e.g. for approval, is removed by canceling the request.
+	_, err = getRoleAssignment(client, objectId, subjectId, roleDefinition.ID, assignmentState)
+	if errors.Is(err, errRoleAssignmentNotFound) {
+		request, err := getPendingRoleAssignmentRequest(client, d.Get("request_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if request != nil {
+			log.Printf("[INFO] Canceling pending role assignment request %s", request.ID)
+			err = cancelRoleAssignmentRequest(client, request.ID)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	request, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+		ResourceID:       objectId,
+		RoleDefinitionID: roleDefinition.ID,
+		SubjectID:        subjectId,
+		AssignmentState:  assignmentState,
+		Type:             "AdminRemove",
+		Reason:           d.Get("justification").(string),
+		TicketNumber:     d.Get("ticket_number").(string),
+		TicketSystem:     d.Get("ticket_system").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, diags := waitForRoleAssignmentRequest(ctx, client, request, d.Timeout(schema.TimeoutDelete))
+	if diags.HasError() {
+		return diags
+	}
+
 	d.SetId("")
 
 	return diags
 }
+
+// getRoleAssignmentSubjectId returns the configured subject ID, or resolves the subject's name through Microsoft Graph.
+func getRoleAssignmentSubjectId(client *Client, d *schema.ResourceData) (string, error) {
+	if v, ok := d.GetOk("subject_user_principal_name"); ok {
+		return resolveUserObjectID(client, v.(string))
+	}
+	if v, ok := d.GetOk("subject_group_name"); ok {
+		return resolveGroupObjectID(client, v.(string))
+	}
+	return d.Get("subject_id").(string), nil
+}
+
+// getRoleAssignmentSchedule builds the schedule sent with a request. Without a configured start time,
+// requests for an existing assignment keep its start time unless it has already expired.
+func getRoleAssignmentSchedule(d *schema.ResourceData, current *RoleAssignment) *RoleAssignmentSchedule {
+	now := time.Now().UTC()
+	schedule := RoleAssignmentSchedule{
+		Type:          "Once",
+		StartDateTime: &now,
+	}
+
+	// Timestamps and durations have already been checked by their ValidateFunc.
+	if v, ok := d.GetOk("start_date_time"); ok {
+		startDateTime, _ := time.Parse(time.RFC3339, v.(string))
+		schedule.StartDateTime = &startDateTime
+	} else if current != nil && current.StartDateTime != nil && (current.EndDateTime == nil || current.EndDateTime.After(now)) {
+		schedule.StartDateTime = current.StartDateTime
+	}
+
+	if v, ok := d.GetOk("end_date_time"); ok {
+		endDateTime, _ := time.Parse(time.RFC3339, v.(string))
+		schedule.EndDateTime = &endDateTime
+	} else if v, ok := d.GetOk("duration"); ok {
+		duration, _ := parseISO8601Duration(v.(string))
+		endDateTime := schedule.StartDateTime.Add(duration)
+		schedule.EndDateTime = &endDateTime
+	}
+
+	return &schedule
+}
+
+// validateRoleAssignmentSchedule checks an assignment schedule against the group's role settings,
+// so that requests PIM would reject fail with a readable error.
+func validateRoleAssignmentSchedule(client *Client, objectId string, roleDefinitionId string, assignmentState string, schedule *RoleAssignmentSchedule) error {
+	if schedule.EndDateTime != nil && !schedule.EndDateTime.After(*schedule.StartDateTime) {
+		return errors.New("The assignment must end after it starts.")
+	}
+
+	roleSettings, err := client.GetRoleSettings(objectId, roleDefinitionId)
+	if err != nil {
+		return err
+	}
+
+	roleSettingsOptions, err := getRoleSettingsOptions(roleSettings)
+	if err != nil {
+		return err
+	}
+
+	return checkAssignmentSchedule(roleSettingsOptions, assignmentState, schedule)
+}
+
+func checkAssignmentSchedule(roleSettingsOptions *RoleSettingsOptions, assignmentState string, schedule *RoleAssignmentSchedule) error {
+	allowPermanent := roleSettingsOptions.AllowPermanentEligibleAssignments
+	maxMins := roleSettingsOptions.MaxEligibleAssignmentTimeMins
+	if assignmentState == "Active" {
+		allowPermanent = roleSettingsOptions.AllowPermanentActiveAssignments
+		maxMins = roleSettingsOptions.MaxActiveAssignmentTimeMins
+	}
+
+	if allowPermanent {
+		return nil
+	}
+
+	if schedule.EndDateTime == nil {
+		return fmt.Errorf("The group's role settings do not allow permanent %s assignments, set end_date_time or duration.", strings.ToLower(assignmentState))
+	}
+
+	maxDuration := time.Duration(maxMins) * time.Minute
+	if maxDuration > 0 && schedule.EndDateTime.Sub(*schedule.StartDateTime) > maxDuration {
+		return fmt.Errorf("The assignment lasts longer than the maximum of %d minutes allowed by the group's role settings.", maxMins)
+	}
+
+	return nil
+}
+
+// waitForRoleAssignmentRequest polls a submitted request until PIM stops processing it, and turns
+// denied, canceled or failed requests into an error. A request still pending when the timeout expires,
+// e.g. one waiting for approval, is returned with a warning.
+func waitForRoleAssignmentRequest(ctx context.Context, client *Client, request *RoleAssignmentRequestApiResponse, timeout time.Duration) (*RoleAssignmentRequestApiResponse, diag.Diagnostics) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if request.Status == nil {
+			return request, nil
+		}
+
+		if containsString(roleAssignmentRequestFailedSubStatuses, request.Status.SubStatus) {
+			return request, diag.Diagnostics{
+				{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Role assignment request %s was not fulfilled: %s.", request.ID, request.Status.SubStatus),
+					Detail:   formatRoleAssignmentRequestStatusDetails(request.Status),
+				},
+			}
+		}
+
+		if !containsString(roleAssignmentRequestPendingSubStatuses, request.Status.SubStatus) {
+			return request, nil
+		}
+
+		if time.Now().After(deadline) {
+			return request, diag.Diagnostics{
+				{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("Role assignment request %s is still %s.", request.ID, request.Status.SubStatus),
+					Detail:   "The request remains open in PIM and is tracked in state. Approve or cancel it there, the next refresh picks up the result.",
+				},
+			}
+		}
+
+		log.Printf("[DEBUG] Role assignment request %s is %s, waiting", request.ID, request.Status.SubStatus)
+
+		select {
+		case <-ctx.Done():
+			return request, diag.FromErr(ctx.Err())
+		case <-time.After(roleAssignmentRequestPollInterval):
+		}
+
+		var err error
+		request, err = getRoleAssignmentRequest(client, request.ID)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+}
+
+func isRoleAssignmentRequestPending(request *RoleAssignmentRequestApiResponse) bool {
+	return request != nil && request.Status != nil && containsString(roleAssignmentRequestPendingSubStatuses, request.Status.SubStatus)
+}
+
+// getPendingRoleAssignmentRequest returns the request if PIM is still processing it, and nil otherwise.
+func getPendingRoleAssignmentRequest(client *Client, requestId string) (*RoleAssignmentRequestApiResponse, error) {
+	if requestId == "" {
+		return nil, nil
+	}
+
+	request, err := getRoleAssignmentRequest(client, requestId)
+	if isNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRoleAssignmentRequestPending(request) {
+		return nil, nil
+	}
+	return request, nil
+}
+
+func setRoleAssignmentRequestStatus(d *schema.ResourceData, request *RoleAssignmentRequestApiResponse) {
+	d.Set("request_id", request.ID)
+	if request.Status != nil {
+		d.Set("status", request.Status.Status)
+		d.Set("sub_status", request.Status.SubStatus)
+	}
+	if request.Schedule != nil {
+		d.Set("schedule_start", formatDateTime(request.Schedule.StartDateTime))
+		d.Set("schedule_end", formatDateTime(request.Schedule.EndDateTime))
+	}
+}
+
+func formatRoleAssignmentRequestStatusDetails(status *RoleAssignmentRequestStatus) string {
+	details := make([]string, 0, len(status.StatusDetails))
+	for _, item := range status.StatusDetails {
+		details = append(details, fmt.Sprintf("%s: %s", item.Key, item.Value))
+	}
+	return strings.Join(details, "\n")
+}
+
+func formatDateTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
+
+// getRoleAssignmentUpdateType picks the PIM request type that moves an existing assignment to a new end time.
+func getRoleAssignmentUpdateType(current *RoleAssignment, endDateTime *time.Time, now time.Time) string {
+	if current.EndDateTime == nil {
+		return "AdminUpdate"
+	}
+	if current.EndDateTime.Before(now) {
+		return "AdminRenew"
+	}
+	if endDateTime != nil && endDateTime.After(*current.EndDateTime) && current.EndDateTime.Sub(now) <= roleAssignmentExtendWindow {
+		return "AdminExtend"
+	}
+	return "AdminUpdate"
+}
@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestGetRoleAssignmentUpdateType(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+
+	cases := map[string]struct {
+		current     *RoleAssignment
+		endDateTime *time.Time
+		expected    string
+	}{
+		"permanent": {
+			current:     &RoleAssignment{},
+			endDateTime: at(24 * time.Hour),
+			expected:    "AdminUpdate",
+		},
+		"expired": {
+			current:     &RoleAssignment{EndDateTime: at(-time.Hour)},
+			endDateTime: at(30 * 24 * time.Hour),
+			expected:    "AdminRenew",
+		},
+		"expiring soon": {
+			current:     &RoleAssignment{EndDateTime: at(48 * time.Hour)},
+			endDateTime: at(30 * 24 * time.Hour),
+			expected:    "AdminExtend",
+		},
+		"expiring later": {
+			current:     &RoleAssignment{EndDateTime: at(60 * 24 * time.Hour)},
+			endDateTime: at(90 * 24 * time.Hour),
+			expected:    "AdminUpdate",
+		},
+		"shortened": {
+			current:     &RoleAssignment{EndDateTime: at(48 * time.Hour)},
+			endDateTime: at(24 * time.Hour),
+			expected:    "AdminUpdate",
+		},
+	}
+
+	for name, c := range cases {
+		if actual := getRoleAssignmentUpdateType(c.current, c.endDateTime, now); actual != c.expected {
+			t.Errorf("%s: expected %s, got %s", name, c.expected, actual)
+		}
+	}
+}
+
+func TestCheckAssignmentSchedule(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(90 * 24 * time.Hour)
+
+	limited := &RoleSettingsOptions{MaxEligibleAssignmentTimeMins: 60 * 24 * 30, AllowPermanentActiveAssignments: true}
+	permanent := &RoleSettingsOptions{AllowPermanentEligibleAssignments: true}
+
+	if err := checkAssignmentSchedule(permanent, "Eligible", &RoleAssignmentSchedule{StartDateTime: &start}); err != nil {
+		t.Errorf("permanent assignment allowed: unexpected error: %s", err)
+	}
+	if err := checkAssignmentSchedule(limited, "Eligible", &RoleAssignmentSchedule{StartDateTime: &start}); err == nil {
+		t.Error("permanent assignment not allowed: expected an error")
+	}
+	if err := checkAssignmentSchedule(limited, "Eligible", &RoleAssignmentSchedule{StartDateTime: &start, EndDateTime: &end}); err == nil {
+		t.Error("assignment over maximum: expected an error")
+	}
+
+	end = start.Add(7 * 24 * time.Hour)
+	if err := checkAssignmentSchedule(limited, "Eligible", &RoleAssignmentSchedule{StartDateTime: &start, EndDateTime: &end}); err != nil {
+		t.Errorf("assignment within maximum: unexpected error: %s", err)
+	}
+
+	if err := checkAssignmentSchedule(limited, "Active", &RoleAssignmentSchedule{StartDateTime: &start}); err != nil {
+		t.Errorf("permanent active assignment allowed: unexpected error: %s", err)
+	}
+	if err := checkAssignmentSchedule(permanent, "Active", &RoleAssignmentSchedule{StartDateTime: &start}); err == nil {
+		t.Error("permanent active assignment not allowed: expected an error")
+	}
+}
+
+func TestWaitForRoleAssignmentRequest(t *testing.T) {
+	defer func(interval time.Duration) { roleAssignmentRequestPollInterval = interval }(roleAssignmentRequestPollInterval)
+	roleAssignmentRequestPollInterval = time.Millisecond
+
+	cases := map[string]struct {
+		subStatuses []string
+		timeout     time.Duration
+		severity    diag.Severity
+		diagnostics int
+		subStatus   string
+	}{
+		"provisioned": {
+			subStatuses: []string{"PendingEvaluation", "Provisioned"},
+			timeout:     time.Minute,
+			subStatus:   "Provisioned",
+		},
+		"denied": {
+			subStatuses: []string{"PendingApproval", "AdminDenied"},
+			timeout:     time.Minute,
+			severity:    diag.Error,
+			diagnostics: 1,
+			subStatus:   "AdminDenied",
+		},
+		"still pending": {
+			subStatuses: []string{"PendingApproval"},
+			timeout:     20 * time.Millisecond,
+			severity:    diag.Warning,
+			diagnostics: 1,
+			subStatus:   "PendingApproval",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			polls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				subStatus := c.subStatuses[len(c.subStatuses)-1]
+				if polls < len(c.subStatuses) {
+					subStatus = c.subStatuses[polls]
+				}
+				polls++
+				fmt.Fprintf(w, `{"id":"request","status":{"status":"InProgress","subStatus":%q}}`, subStatus)
+			}))
+			defer server.Close()
+
+			client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+			request := &RoleAssignmentRequestApiResponse{ID: "request", Status: &RoleAssignmentRequestStatus{SubStatus: "Accepted"}}
+
+			request, diags := waitForRoleAssignmentRequest(context.Background(), client, request, c.timeout)
+			if len(diags) != c.diagnostics {
+				t.Fatalf("expected %d diagnostics, got %#v", c.diagnostics, diags)
+			}
+			if c.diagnostics > 0 && diags[0].Severity != c.severity {
+				t.Errorf("expected severity %v, got %v", c.severity, diags[0].Severity)
+			}
+			if request.Status.SubStatus != c.subStatus {
+				t.Errorf("expected sub-status %s, got %s", c.subStatus, request.Status.SubStatus)
+			}
+		})
+	}
+}
+
+func TestResourceRoleAssignmentRequestDelete(t *testing.T) {
+	defer func(interval time.Duration) { roleAssignmentRequestPollInterval = interval }(roleAssignmentRequestPollInterval)
+	roleAssignmentRequestPollInterval = time.Millisecond
+
+	cases := map[string]struct {
+		assignments string
+		expected    []string
+	}{
+		"pending request": {
+			assignments: `{"value":[]}`,
+			expected:    []string{"GET request", "POST request/cancel"},
+		},
+		"assignment": {
+			assignments: `{"value":[{"id":"assignment"}]}`,
+			expected:    []string{"POST AdminRemove", "GET remove"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/privilegedAccess/aadGroups/resources/group/roleDefinitions":
+					fmt.Fprint(w, `{"value":[{"id":"definition"}]}`)
+				case "/privilegedAccess/aadGroups/roleAssignments":
+					fmt.Fprint(w, c.assignments)
+				case "/privilegedAccess/aadGroups/roleAssignmentRequests":
+					calls = append(calls, "POST AdminRemove")
+					fmt.Fprint(w, `{"id":"remove","status":{"status":"InProgress","subStatus":"PendingEvaluation"}}`)
+				case "/privilegedAccess/aadGroups/roleAssignmentRequests/remove":
+					calls = append(calls, "GET remove")
+					fmt.Fprint(w, `{"id":"remove","status":{"status":"Closed","subStatus":"Provisioned"}}`)
+				case "/privilegedAccess/aadGroups/roleAssignmentRequests/request":
+					calls = append(calls, "GET request")
+					fmt.Fprint(w, `{"id":"request","status":{"status":"InProgress","subStatus":"PendingApproval"}}`)
+				case "/privilegedAccess/aadGroups/roleAssignmentRequests/request/cancel":
+					calls = append(calls, "POST request/cancel")
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+			d := schema.TestResourceDataRaw(t, resourceRoleAssignmentRequest().Schema, map[string]interface{}{
+				"object_id":        "group",
+				"subject_id":       "subject",
+				"assignment_state": "Eligible",
+				"role_name":        "Member",
+			})
+			d.SetId("request")
+			d.Set("request_id", "request")
+
+			if diags := resourceRoleAssignmentRequestDelete(context.Background(), d, client); diags.HasError() {
+				t.Fatalf("unexpected error: %#v", diags)
+			}
+			if d.Id() != "" {
+				t.Error("expected the resource to be removed from state")
+			}
+			if fmt.Sprint(calls) != fmt.Sprint(c.expected) {
+				t.Errorf("expected calls %v, got %v", c.expected, calls)
+			}
+		})
+	}
+}
+
+func TestResourceRoleAssignmentRequestRead(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	cases := map[string]struct {
+		assignments string
+		request     string
+		removed     bool
+		subStatus   string
+	}{
+		"empty listing": {
+			assignments: `{"value":[]}`,
+			removed:     true,
+		},
+		"expired": {
+			assignments: fmt.Sprintf(`{"value":[{"id":"assignment","endDateTime":%q}]}`, expired),
+			request:     `{"id":"request","status":{"status":"Closed","subStatus":"Provisioned"}}`,
+			removed:     true,
+		},
+		"pending request": {
+			assignments: `{"value":[]}`,
+			request:     `{"id":"request","status":{"status":"InProgress","subStatus":"PendingApproval"}}`,
+			subStatus:   "PendingApproval",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/privilegedAccess/aadGroups/resources/group/roleDefinitions":
+					fmt.Fprint(w, `{"value":[{"id":"definition"}]}`)
+				case "/privilegedAccess/aadGroups/roleAssignments":
+					fmt.Fprint(w, c.assignments)
+				case "/privilegedAccess/aadGroups/roleAssignmentRequests/request":
+					if c.request == "" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, c.request)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+			d := schema.TestResourceDataRaw(t, resourceRoleAssignmentRequest().Schema, map[string]interface{}{
+				"object_id":        "group",
+				"subject_id":       "subject",
+				"assignment_state": "Eligible",
+				"role_name":        "Member",
+			})
+			d.SetId("request")
+			d.Set("request_id", "request")
+
+			if diags := resourceRoleAssignmentRequestRead(context.Background(), d, client); diags.HasError() {
+				t.Fatalf("unexpected error: %#v", diags)
+			}
+			if removed := d.Id() == ""; removed != c.removed {
+				t.Errorf("expected removed from state to be %t, got %t", c.removed, removed)
+			}
+			if !c.removed && d.Get("sub_status").(string) != c.subStatus {
+				t.Errorf("expected sub-status %s, got %s", c.subStatus, d.Get("sub_status"))
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRoleAssignmentSet() *schema.Resource {
+	return &schema.Resource{
+		Description: "Manages the assignments of one role and state on a Privileged Access Group for a set of subjects. Only the subjects listed here are added or removed, other assignments on the group are left alone. Subjects that already hold the assignment when they are added become managed by this resource.",
+
+		CreateContext: resourceRoleAssignmentSetCreate,
+		ReadContext:   resourceRoleAssignmentSetRead,
+		UpdateContext: resourceRoleAssignmentSetUpdate,
+		DeleteContext: resourceRoleAssignmentSetDelete,
+
+		Schema: map[string]*schema.Schema{
+			"role_definition_id": {
+				Description: "ID of the assigned role definition",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_name": {
+				Description:      "Group role to assign, either `Owner` or `Member` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateRoleName,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(roleNames),
+			},
+			"assignment_state": {
+				Description:      "Either `Eligible` or `Active` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateAssignmentState,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(assignmentStates),
+			},
+			"subject_ids": {
+				Description: "Object IDs of the users or groups managed by this resource.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsUUID,
+				},
+			},
+			"parallelism": {
+				Description:  "Maximum number of assignment requests submitted at the same time.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 50),
+			},
+			"end_date_time": {
+				Description:   "RFC3339 timestamp at which added assignments expire. Omit both this and `duration` for permanent assignments. Changing it does not affect subjects that already hold the role.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"duration"},
+			},
+			"duration": {
+				Description:   "ISO 8601 duration of added assignments counted from the time they are requested, e.g. `P30D` or `PT8H`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateISO8601Duration,
+				ConflictsWith: []string{"end_date_time"},
+			},
+			"justification": {
+				Description: "Reason recorded with every request PIM receives for these assignments.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ticket_number": {
+				Description: "Ticket number recorded with every request PIM receives for these assignments.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ticket_system": {
+				Description: "Name of the ticketing system `ticket_number` belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceRoleAssignmentSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", objectId, roleName, assignmentState))
+
+	return resourceRoleAssignmentSetUpdate(ctx, d, meta)
+}
+
+func resourceRoleAssignmentSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinition.ID, assignmentState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only subjects managed here are tracked, so assignments made elsewhere do not show up as drift.
+	current := getDirectAssignmentSubjectIds(roleAssignments, time.Now())
+	managed := d.Get("subject_ids").(*schema.Set)
+
+	d.Set("subject_ids", managed.Intersection(current).List())
+	d.Set("role_definition_id", roleDefinition.ID)
+
+	return diags
+}
+
+func resourceRoleAssignmentSetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+	parallelism := d.Get("parallelism").(int)
+	// ResourceData is not safe for concurrent use, so everything the requests need is read up front.
+	justification := d.Get("justification").(string)
+	ticketNumber := d.Get("ticket_number").(string)
+	ticketSystem := d.Get("ticket_system").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinition.ID, assignmentState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current := getDirectAssignmentSubjectIds(roleAssignments, time.Now())
+	oldSubjectIds, newSubjectIds := d.GetChange("subject_ids")
+	desired := newSubjectIds.(*schema.Set)
+	removed := oldSubjectIds.(*schema.Set).Difference(desired).Intersection(current)
+
+	added := desired.Difference(current)
+	if added.Len() > 0 {
+		schedule := getRoleAssignmentSchedule(d, nil)
+		err = validateRoleAssignmentSchedule(client, objectId, roleDefinition.ID, assignmentState, schedule)
+		if err != nil {
+			d.Set("subject_ids", desired.Union(removed).List())
+			return append(resourceRoleAssignmentSetRead(ctx, d, meta), diag.FromErr(err)...)
+		}
+
+		_, addDiags := forEachSubjectId(added, parallelism, "add", func(subjectId string) error {
+			_, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+				ResourceID:       objectId,
+				RoleDefinitionID: roleDefinition.ID,
+				SubjectID:        subjectId,
+				AssignmentState:  assignmentState,
+				Type:             "AdminAdd",
+				Reason:           justification,
+				TicketNumber:     ticketNumber,
+				TicketSystem:     ticketSystem,
+				Schedule:         schedule,
+			})
+			return err
+		})
+		diags = append(diags, addDiags...)
+	}
+
+	failedRemovals, removeDiags := forEachSubjectId(removed, parallelism, "remove", func(subjectId string) error {
+		return removeRoleAssignmentSetSubject(client, objectId, roleDefinition.ID, assignmentState, subjectId, justification, ticketNumber, ticketSystem)
+	})
+	diags = append(diags, removeDiags...)
+
+	// Subjects that could not be removed stay managed so the next apply retries them.
+	d.Set("subject_ids", desired.Union(failedRemovals).List())
+
+	return append(diags, resourceRoleAssignmentSetRead(ctx, d, meta)...)
+}
+
+func resourceRoleAssignmentSetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+	parallelism := d.Get("parallelism").(int)
+	justification := d.Get("justification").(string)
+	ticketNumber := d.Get("ticket_number").(string)
+	ticketSystem := d.Get("ticket_system").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	failedRemovals, diags := forEachSubjectId(d.Get("subject_ids").(*schema.Set), parallelism, "remove", func(subjectId string) error {
+		return removeRoleAssignmentSetSubject(client, objectId, roleDefinition.ID, assignmentState, subjectId, justification, ticketNumber, ticketSystem)
+	})
+	if diags.HasError() {
+		d.Set("subject_ids", failedRemovals.List())
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func removeRoleAssignmentSetSubject(client *Client, objectId string, roleDefinitionId string, assignmentState string, subjectId string, justification string, ticketNumber string, ticketSystem string) error {
+	_, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+		ResourceID:       objectId,
+		RoleDefinitionID: roleDefinitionId,
+		SubjectID:        subjectId,
+		AssignmentState:  assignmentState,
+		Type:             "AdminRemove",
+		Reason:           justification,
+		TicketNumber:     ticketNumber,
+		TicketSystem:     ticketSystem,
+	})
+	return err
+}
+
+// forEachSubjectId runs fn for every subject with at most parallelism calls in flight. It returns the
+// subjects fn failed for, together with a single diagnostic describing every failure.
+func forEachSubjectId(subjectIds *schema.Set, parallelism int, action string, fn func(subjectId string) error) (*schema.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	failed := schema.NewSet(schema.HashString, nil)
+	errs := []string{}
+	semaphore := make(chan struct{}, parallelism)
+
+	for _, item := range subjectIds.List() {
+		subjectId := item.(string)
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := fn(subjectId)
+			if err != nil {
+				mutex.Lock()
+				defer mutex.Unlock()
+				failed.Add(subjectId)
+				errs = append(errs, fmt.Sprintf("%s: %s", subjectId, err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to %s %d of %d role assignments.", action, len(errs), subjectIds.Len()),
+			Detail:   strings.Join(errs, "\n"),
+		})
+	}
+
+	return failed, diags
+}
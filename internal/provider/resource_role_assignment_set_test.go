@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestForEachSubjectId(t *testing.T) {
+	subjectIds := schema.NewSet(schema.HashString, []interface{}{"a", "b", "c", "d", "e"})
+
+	var inFlight, maxInFlight int32
+	failed, diags := forEachSubjectId(subjectIds, 2, "add", func(subjectId string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		if subjectId == "b" || subjectId == "d" {
+			return errors.New("denied")
+		}
+		return nil
+	})
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 calls in flight, got %d", maxInFlight)
+	}
+	if failed.Len() != 2 || !failed.Contains("b") || !failed.Contains("d") {
+		t.Errorf("unexpected failed subjects %v", failed.List())
+	}
+	if len(diags) != 1 || !diags.HasError() {
+		t.Fatalf("expected a single error diagnostic, got %v", diags)
+	}
+	if diags[0].Summary != "Failed to add 2 of 5 role assignments." || !strings.Contains(diags[0].Detail, "b: denied\nd: denied") {
+		t.Errorf("unexpected diagnostic %q: %q", diags[0].Summary, diags[0].Detail)
+	}
+}
+
+// TestResourceRoleAssignmentSetRequests submits requests through the Update and Delete closures, run it with -race.
+func TestResourceRoleAssignmentSetRequests(t *testing.T) {
+	fixture, err := ioutil.ReadFile("testdata/role_settings.json")
+	if err != nil {
+		t.Fatalf("reading fixture: %s", err)
+	}
+
+	var mutex sync.Mutex
+	requests := []RoleAssignmentRequestApiRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/roleDefinitions"):
+			w.Write([]byte(`{"value":[{"id":"definition"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/roleAssignments"):
+			w.Write([]byte(`{"value":[]}`))
+		case strings.HasSuffix(r.URL.Path, "/roleSettingsv2"):
+			fmt.Fprintf(w, `{"value":[%s]}`, fixture)
+		case strings.HasSuffix(r.URL.Path, "/roleAssignmentRequests") && r.Method == "POST":
+			request := RoleAssignmentRequestApiRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Errorf("unexpected request body: %s", err)
+			}
+			mutex.Lock()
+			requests = append(requests, request)
+			mutex.Unlock()
+			fmt.Fprintf(w, `{"id":"request-%s"}`, request.SubjectID)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+	subjectIds := []interface{}{"a", "b", "c", "d", "e", "f"}
+	raw := map[string]interface{}{
+		"object_id":        "group",
+		"role_name":        "Member",
+		"assignment_state": "Eligible",
+		"subject_ids":      subjectIds,
+		"parallelism":      3,
+		"duration":         "P7D",
+		"justification":    "Onboarding",
+		"ticket_number":    "CHG-1",
+		"ticket_system":    "ServiceNow",
+	}
+
+	actions := map[string]func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics{
+		"AdminAdd":    resourceRoleAssignmentSetUpdate,
+		"AdminRemove": resourceRoleAssignmentSetDelete,
+	}
+	for action, fn := range actions {
+		requests = nil
+		d := schema.TestResourceDataRaw(t, resourceRoleAssignmentSet().Schema, raw)
+		d.SetId("group/Member/Eligible")
+
+		if diags := fn(context.Background(), d, client); diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics %v", action, diags)
+		}
+
+		if len(requests) != len(subjectIds) {
+			t.Fatalf("%s: expected %d requests, got %d", action, len(subjectIds), len(requests))
+		}
+		for _, request := range requests {
+			if request.Type != action || request.ResourceID != "group" || request.RoleDefinitionID != "definition" || request.AssignmentState != "Eligible" ||
+				request.Reason != "Onboarding" || request.TicketNumber != "CHG-1" || request.TicketSystem != "ServiceNow" {
+				t.Errorf("%s: unexpected request %+v", action, request)
+			}
+			if action == "AdminAdd" && (request.Schedule == nil || request.Schedule.EndDateTime == nil) {
+				t.Errorf("%s: expected a schedule ending after the duration, got %+v", action, request.Schedule)
+			}
+		}
+	}
+}
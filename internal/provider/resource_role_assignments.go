@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRoleAssignments() *schema.Resource {
+	return &schema.Resource{
+		Description: "Authoritatively manages the direct assignments of one role and state on a Privileged Access Group. Subjects missing from `subject_ids` are removed from the group, including assignments made outside Terraform. Inherited assignments are ignored.",
+
+		CreateContext: resourceRoleAssignmentsCreate,
+		ReadContext:   resourceRoleAssignmentsRead,
+		UpdateContext: resourceRoleAssignmentsUpdate,
+		DeleteContext: resourceRoleAssignmentsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"role_definition_id": {
+				Description: "ID of the assigned role definition",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"object_id": {
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
+			},
+			"role_name": {
+				Description:      "Group role to assign, either `Owner` or `Member` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateRoleName,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(roleNames),
+			},
+			"assignment_state": {
+				Description:      "Either `Eligible` or `Active` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateAssignmentState,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(assignmentStates),
+			},
+			"subject_ids": {
+				Description: "Object IDs of every user or group that should hold the role. An empty set removes all direct assignments.",
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsUUID,
+				},
+			},
+			"end_date_time": {
+				Description:   "RFC3339 timestamp at which added assignments expire. Omit both this and `duration` for permanent assignments. Changing it does not affect subjects that already hold the role.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validation.IsRFC3339Time,
+				ConflictsWith: []string{"duration"},
+			},
+			"duration": {
+				Description:   "ISO 8601 duration of added assignments counted from the time they are requested, e.g. `P30D` or `PT8H`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateISO8601Duration,
+				ConflictsWith: []string{"end_date_time"},
+			},
+			"justification": {
+				Description: "Reason recorded with every request PIM receives for these assignments.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ticket_number": {
+				Description: "Ticket number recorded with every request PIM receives for these assignments.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ticket_system": {
+				Description: "Name of the ticketing system `ticket_number` belongs to.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceRoleAssignmentsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", objectId, roleName, assignmentState))
+
+	return resourceRoleAssignmentsUpdate(ctx, d, meta)
+}
+
+func resourceRoleAssignmentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinition.ID, assignmentState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("subject_ids", getDirectAssignmentSubjectIds(roleAssignments, time.Now()).List())
+	d.Set("role_definition_id", roleDefinition.ID)
+
+	return diags
+}
+
+func resourceRoleAssignmentsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+	justification := d.Get("justification").(string)
+	ticketNumber := d.Get("ticket_number").(string)
+	ticketSystem := d.Get("ticket_system").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Compare against the group itself rather than prior state, so out-of-band assignments are removed too.
+	roleAssignments, err := listRoleAssignments(client, objectId, roleDefinition.ID, assignmentState)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current := getDirectAssignmentSubjectIds(roleAssignments, time.Now())
+	desired := d.Get("subject_ids").(*schema.Set)
+
+	added := desired.Difference(current)
+	if added.Len() > 0 {
+		schedule := getRoleAssignmentSchedule(d, nil)
+		err = validateRoleAssignmentSchedule(client, objectId, roleDefinition.ID, assignmentState, schedule)
+		if err != nil {
+			return append(resourceRoleAssignmentsRead(ctx, d, meta), diag.FromErr(err)...)
+		}
+
+		_, addDiags := forEachSubjectId(added, 1, "add", func(subjectId string) error {
+			_, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+				ResourceID:       objectId,
+				RoleDefinitionID: roleDefinition.ID,
+				SubjectID:        subjectId,
+				AssignmentState:  assignmentState,
+				Type:             "AdminAdd",
+				Reason:           justification,
+				TicketNumber:     ticketNumber,
+				TicketSystem:     ticketSystem,
+				Schedule:         schedule,
+			})
+			return err
+		})
+		diags = append(diags, addDiags...)
+	}
+
+	_, removeDiags := forEachSubjectId(current.Difference(desired), 1, "remove", func(subjectId string) error {
+		log.Printf("[INFO] Removing %s %s assignment of %s on group %s", assignmentState, roleName, subjectId, objectId)
+		_, err := submitRoleAssignmentRequest(client, &RoleAssignmentRequestApiRequest{
+			ResourceID:       objectId,
+			RoleDefinitionID: roleDefinition.ID,
+			SubjectID:        subjectId,
+			AssignmentState:  assignmentState,
+			Type:             "AdminRemove",
+			Reason:           justification,
+			TicketNumber:     ticketNumber,
+			TicketSystem:     ticketSystem,
+		})
+		return err
+	})
+	diags = append(diags, removeDiags...)
+
+	// Reading back records the subjects that were actually added or removed, so failed ones are retried next apply.
+	return append(diags, resourceRoleAssignmentsRead(ctx, d, meta)...)
+}
+
+func resourceRoleAssignmentsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+	assignmentState := d.Get("assignment_state").(string)
+	justification := d.Get("justification").(string)
+	ticketNumber := d.Get("ticket_number").(string)
+	ticketSystem := d.Get("ticket_system").(string)
+
+	roleDefinition, err := client.GetRoleDefinition(objectId, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	failedRemovals, diags := forEachSubjectId(d.Get("subject_ids").(*schema.Set), 1, "remove", func(subjectId string) error {
+		return removeRoleAssignmentSetSubject(client, objectId, roleDefinition.ID, assignmentState, subjectId, justification, ticketNumber, ticketSystem)
+	})
+	if diags.HasError() {
+		// Only the subjects that are still assigned remain in state.
+		d.Set("subject_ids", failedRemovals.List())
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// getDirectAssignmentSubjectIds returns the subjects holding an unexpired assignment of their own, ignoring activations.
+// Inherited assignments come from group membership and cannot be removed on this group.
+func getDirectAssignmentSubjectIds(roleAssignments []RoleAssignment, now time.Time) *schema.Set {
+	result := schema.NewSet(schema.HashString, nil)
+	for _, item := range roleAssignments {
+		if item.MemberType == "Inherited" {
+			continue
+		}
+		// Activations of an eligible assignment show up as active assignments, but are owned by the eligibility.
+		if item.LinkedEligibleRoleAssignmentID != "" {
+			continue
+		}
+		if item.EndDateTime != nil && item.EndDateTime.Before(now) {
+			continue
+		}
+		result.Add(item.SubjectID)
+	}
+	return result
+}
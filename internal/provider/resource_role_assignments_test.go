@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+func TestGetDirectAssignmentSubjectIds(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	subjectIds := getDirectAssignmentSubjectIds([]RoleAssignment{
+		{SubjectID: "permanent", MemberType: "Direct"},
+		{SubjectID: "current", MemberType: "Direct", EndDateTime: &future},
+		{SubjectID: "expired", MemberType: "Direct", EndDateTime: &past},
+		{SubjectID: "inherited", MemberType: "Inherited"},
+		{SubjectID: "activated", MemberType: "Direct", EndDateTime: &future, LinkedEligibleRoleAssignmentID: "eligible"},
+	}, now)
+
+	if subjectIds.Len() != 2 || !subjectIds.Contains("permanent") || !subjectIds.Contains("current") {
+		t.Errorf("unexpected subject IDs %v", subjectIds.List())
+	}
+}
+
+func TestResourceRoleAssignmentsDeleteKeepsFailedSubjects(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/roleDefinitions") {
+			w.Write([]byte(`{"value":[{"id":"definition"}]}`))
+			return
+		}
+
+		requests++
+		request := RoleAssignmentRequestApiRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("unexpected request body: %s", err)
+		}
+		if request.SubjectID == "b" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"id":"request"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+	d := schema.TestResourceDataRaw(t, resourceRoleAssignments().Schema, map[string]interface{}{
+		"object_id":        "group",
+		"role_name":        "Member",
+		"assignment_state": "Eligible",
+		"subject_ids":      []interface{}{"a", "b", "c"},
+	})
+	d.SetId("group/Member/Eligible")
+
+	diags := resourceRoleAssignmentsDelete(context.Background(), d, client)
+	if len(diags) != 1 || !diags.HasError() {
+		t.Fatalf("expected a single error diagnostic, got %v", diags)
+	}
+	if requests != 3 {
+		t.Errorf("expected every subject to be attempted, got %d requests", requests)
+	}
+	if subjectIds := d.Get("subject_ids").(*schema.Set); subjectIds.Len() != 1 || !subjectIds.Contains("b") {
+		t.Errorf("expected only the failed subject to remain, got %v", subjectIds.List())
+	}
+	if d.Id() == "" {
+		t.Error("expected the resource to stay in state")
+	}
+}
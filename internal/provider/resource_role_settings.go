@@ -4,19 +4,129 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/oskarm93/azurepag-client-go"
 )
 
 type RoleSettingsOptions struct {
-	AllowPermanentEligibleAssignments bool
-	MaxEligibleAssignmentTimeMins     int
-	MaxActivationTimeMins             int
-	RequireMFAOnActivation            bool
-	RequireJustificationOnActivation  bool
-	RequireTicketInfoOnActivation     bool
+	AllowPermanentEligibleAssignments      bool
+	MaxEligibleAssignmentTimeMins          int
+	MaxActivationTimeMins                  int
+	RequireMFAOnActivation                 bool
+	RequireJustificationOnActivation       bool
+	RequireTicketInfoOnActivation          bool
+	RequireApprovalOnActivation            bool
+	Approvers                              []RoleSettingsApprover
+	AllowPermanentActiveAssignments        bool
+	MaxActiveAssignmentTimeMins            int
+	RequireMFAOnActiveAssignment           bool
+	RequireJustificationOnActiveAssignment bool
+	Notifications                          []RoleSettingsNotification
+	ActivationAuthenticationContext        string
+	AdditionalRules                        []RoleSettingsAdditionalRule
+}
+
+// RoleSettingsAdditionalRule is a rule the provider does not model, overlaid onto the role's settings as is.
+type RoleSettingsAdditionalRule struct {
+	Caller         string
+	Level          string
+	Operation      string
+	RuleIdentifier string
+	Setting        map[string]interface{}
+}
+
+// RoleSettingsAcrsRuleSetting requires an authentication context, enforced through Conditional Access.
+type RoleSettingsAcrsRuleSetting struct {
+	AcrsRequired bool    `json:"acrsRequired"`
+	Acrs         *string `json:"acrs"`
+}
+
+type RoleSettingsNotification struct {
+	Type                 string
+	RecipientType        string
+	DefaultRecipients    bool
+	AdditionalRecipients []string
+	CriticalOnly         bool
+}
+
+type RoleSettingsNotificationRuleSetting struct {
+	Policies []RoleSettingsNotificationPolicy `json:"policies"`
+}
+
+type RoleSettingsNotificationPolicy struct {
+	DeliveryMechanism string                                  `json:"deliveryMechanism"`
+	Setting           []RoleSettingsNotificationPolicySetting `json:"setting"`
+}
+
+type RoleSettingsNotificationPolicySetting struct {
+	CustomReceivers          []string `json:"customreceivers"`
+	IsDefaultReceiverEnabled bool     `json:"isdefaultreceiverenabled"`
+	NotificationLevel        int      `json:"notificationlevel"`
+	RecipientType            int      `json:"recipienttype"`
+}
+
+// roleSettingsNotificationLifecycles maps each notification type to the Caller/Level entry holding its rule.
+var roleSettingsNotificationLifecycles = map[string][2]string{
+	"EligibleAssignment": {"Admin", "Eligible"},
+	"ActiveAssignment":   {"Admin", "Member"},
+	"Activation":         {"EndUser", "Member"},
+}
+
+var roleSettingsNotificationTypes = []string{"EligibleAssignment", "ActiveAssignment", "Activation"}
+
+// roleSettingsNotificationRecipientTypes is indexed by PIM's recipienttype value.
+var roleSettingsNotificationRecipientTypes = []string{"Admin", "Requestor", "Approver"}
+
+const (
+	notificationLevelCritical = 1
+	notificationLevelAll      = 2
+)
+
+type RoleSettingsApprover struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	Type        string `json:"type"`
+}
+
+type RoleSettingsApprovalRuleSetting struct {
+	Enabled   bool                   `json:"enabled"`
+	Approvers []RoleSettingsApprover `json:"approvers"`
+}
+
+// defaultRoleSettingsOptions is the baseline policy applied by reset_to_defaults. It is defined by this
+// provider, not read from PIM, so the reset_to_defaults description has to be kept in line with it.
+var defaultRoleSettingsOptions = RoleSettingsOptions{
+	AllowPermanentEligibleAssignments:      true,
+	MaxEligibleAssignmentTimeMins:          525600,
+	MaxActivationTimeMins:                  480,
+	RequireMFAOnActivation:                 true,
+	RequireJustificationOnActivation:       true,
+	RequireTicketInfoOnActivation:          false,
+	RequireApprovalOnActivation:            false,
+	Approvers:                              []RoleSettingsApprover{},
+	AllowPermanentActiveAssignments:        true,
+	MaxActiveAssignmentTimeMins:            259200,
+	RequireMFAOnActiveAssignment:           false,
+	RequireJustificationOnActiveAssignment: true,
+	Notifications:                          []RoleSettingsNotification{},
+	ActivationAuthenticationContext:        "",
+}
+
+// RoleSettingsExpirationRuleSetting also carries the TimeSpan form of the grant period, which PIM returns
+// alongside the minutes and which must not be left stale when the minutes change.
+type RoleSettingsExpirationRuleSetting struct {
+	azurepag.RoleSettingsExpirationRuleSetting
+	MaximumGrantPeriod string `json:"maximumGrantPeriod"`
 }
 
 func resourceRoleSettings() *schema.Resource {
@@ -28,6 +138,21 @@ func resourceRoleSettings() *schema.Resource {
 		DeleteContext: resourceRoleSettingsDelete,
 		UpdateContext: resourceRoleSettingsUpdate,
 
+		CustomizeDiff: resourceRoleSettingsCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRoleSettingsImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceRoleSettingsV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceRoleSettingsStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"role_definition_id": {
 				Description: "Object ID of the Azure AD group",
@@ -35,59 +160,312 @@ func resourceRoleSettings() *schema.Resource {
 				Computed:    true,
 			},
 			"object_id": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:  "Object ID of the Azure AD group",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsUUID,
 			},
 			"role_name": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+				Description:      "Group role to configure, either `Owner` or `Member` (case-insensitive).",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateRoleName,
+				DiffSuppressFunc: suppressCaseDiff,
+				StateFunc:        canonicalCase(roleNames),
 			},
-			"allow_permanent_eligible_assignments": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeBool,
+			"activation": {
+				Description: "Rules applied when an eligible principal activates the role.",
+				Type:        schema.TypeList,
 				Optional:    true,
 				Computed:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_duration_mins": {
+							Description:   "Longest an activation may last, in minutes.",
+							Type:          schema.TypeInt,
+							Optional:      true,
+							Computed:      true,
+							ConflictsWith: []string{"activation.0.max_duration"},
+						},
+						"max_duration": {
+							Description:   "Longest an activation may last, as a Go or ISO 8601 duration such as `8h` or `PT8H`. Must be between 30 minutes and 24 hours in 30 minute steps.",
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateDuration,
+							ConflictsWith: []string{"activation.0.max_duration_mins"},
+						},
+						"require_mfa": {
+							Description: "Require MFA on activation. Cannot be true together with `authentication_context`.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"authentication_context": {
+							Description:  "Authentication context claim value, e.g. `c1`, that Conditional Access must satisfy on activation. Setting it turns off `require_mfa`, an empty string removes the requirement.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringMatch(regexp.MustCompile(`^(c([1-9]|[1-9][0-9]))?$`), "must be an authentication context ID from c1 to c99"),
+						},
+						"require_justification": {
+							Description: "Require a justification on activation.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"require_ticket_info": {
+							Description: "Require ticket information on activation.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"require_approval": {
+							Description: "Require one of the approvers to approve each activation.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"approver": {
+							Description: "Users or groups allowed to approve activations.",
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"object_id": {
+										Description:  "Object ID of the approving user or group",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.IsUUID,
+									},
+									"type": {
+										Description:  "Either `User` or `Group`.",
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"User", "Group"}, false),
+									},
+								},
+							},
+						},
+					},
+				},
 			},
-			"max_eligible_assignment_time_mins": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeInt,
+			"eligible_assignment": {
+				Description: "Rules applied when an administrator makes an eligible assignment.",
+				Type:        schema.TypeList,
 				Optional:    true,
 				Computed:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_permanent": {
+							Description: "Allow eligible assignments without an end date.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"max_duration_mins": {
+							Description:   "Longest an eligible assignment may last, in minutes, when permanent eligible assignments are not allowed.",
+							Type:          schema.TypeInt,
+							Optional:      true,
+							Computed:      true,
+							ConflictsWith: []string{"eligible_assignment.0.max_duration"},
+						},
+						"max_duration": {
+							Description:   "Longest an eligible assignment may last, as a Go or ISO 8601 duration such as `P365D`. Must be a whole number of days up to a year.",
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateDuration,
+							ConflictsWith: []string{"eligible_assignment.0.max_duration_mins"},
+						},
+					},
+				},
 			},
-			"max_activation_time_mins": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeInt,
+			"active_assignment": {
+				Description: "Rules applied when an administrator makes an active assignment.",
+				Type:        schema.TypeList,
 				Optional:    true,
 				Computed:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allow_permanent": {
+							Description: "Allow active assignments without an end date.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"max_duration_mins": {
+							Description:   "Longest an active assignment may last, in minutes, when permanent active assignments are not allowed.",
+							Type:          schema.TypeInt,
+							Optional:      true,
+							Computed:      true,
+							ConflictsWith: []string{"active_assignment.0.max_duration"},
+						},
+						"max_duration": {
+							Description:   "Longest an active assignment may last, as a Go or ISO 8601 duration such as `P180D`. Must be a whole number of days up to a year.",
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateDuration,
+							ConflictsWith: []string{"active_assignment.0.max_duration_mins"},
+						},
+						"require_mfa": {
+							Description: "Require the administrator to complete MFA when making an active assignment.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+						"require_justification": {
+							Description: "Require a justification when making an active assignment.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+						},
+					},
+				},
 			},
-			"require_mfa_on_activation": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeBool,
+			"notification": {
+				Description: "Email notifications sent for eligible assignments, active assignments and activations. Only the type and recipient type combinations listed here are managed, removing a block leaves the notification as it is.",
+				Type:        schema.TypeSet,
 				Optional:    true,
-				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Description:  "Event the notification is sent for, one of `EligibleAssignment`, `ActiveAssignment` or `Activation`.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(roleSettingsNotificationTypes, false),
+						},
+						"recipient_type": {
+							Description:  "Who receives the notification, one of `Admin`, `Requestor` or `Approver`.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(roleSettingsNotificationRecipientTypes, false),
+						},
+						"default_recipients": {
+							Description: "Send the notification to the default recipients of the recipient type.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"additional_recipients": {
+							Description: "Email addresses that receive the notification in addition to the default recipients.",
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"critical_only": {
+							Description: "Only send critical emails.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+					},
+				},
 			},
-			"require_justification_on_activation": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeBool,
+			"restore_on_destroy": {
+				Description:   "Write the settings the role had before this resource was created back on destroy. It cannot be enabled on imported settings, which have no snapshot to restore.",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"reset_to_defaults"},
+			},
+			"reset_to_defaults": {
+				Description:   "Apply a baseline policy to the role on destroy: permanent eligible and active assignments are allowed, activation lasts up to 8 hours and requires MFA and a justification but no ticket or approval, active assignments require a justification, and notification and additional rules are left as they are.",
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"restore_on_destroy"},
+			},
+			"additional_rule": {
+				Description: "Rules without a dedicated attribute, overlaid onto the matching lifecycle entry on every apply. Only the properties given in `setting` are managed. Rules covered by the other blocks cannot be set here.",
+				Type:        schema.TypeList,
 				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"caller": {
+							Description:  "Either `Admin` or `EndUser`.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"Admin", "EndUser"}, false),
+						},
+						"level": {
+							Description:  "Either `Eligible` or `Member`.",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"Eligible", "Member"}, false),
+						},
+						"operation": {
+							Description: "Operation of the lifecycle entry.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "ALL",
+						},
+						"rule_identifier": {
+							Description: "Identifier of the rule, e.g. `AttributeConditionRule`.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"setting": {
+							Description:      "JSON object with the rule properties to set.",
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validation.StringIsJSON,
+							DiffSuppressFunc: structure.SuppressJsonDiff,
+						},
+					},
+				},
+			},
+			"raw_settings_json": {
+				Description: "Role settings as last read from PIM, including rules this resource does not model.",
+				Type:        schema.TypeString,
 				Computed:    true,
 			},
-			"require_ticket_info_on_activation": {
-				Description: "Object ID of the Azure AD group",
-				Type:        schema.TypeBool,
-				Optional:    true,
+			"planned_payload_json": {
+				Description: "Role settings document sent to PIM on the last apply. During plan it shows the document the next apply will send, and apply fails if the settings were edited in PIM in the meantime. It is unknown during plan while the group is not registered yet.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"original_settings_json": {
+				Description: "Internal snapshot of the role settings taken when this resource was created, used by `restore_on_destroy`.",
+				Type:        schema.TypeString,
 				Computed:    true,
+				Sensitive:   true,
 			},
 		},
 	}
 }
 
+// resourceRoleSettingsImport takes an ID of the form <object_id>/<role_name>. Imported settings have no
+// snapshot for restore_on_destroy.
+func resourceRoleSettingsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Unexpected import ID %q, expected <object_id>/<role_name>.", d.Id())
+	}
+
+	if _, errs := validation.IsUUID(parts[0], "object_id"); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if _, errs := validateRoleName(parts[1], "role_name"); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	d.Set("object_id", parts[0])
+	d.Set("role_name", canonicalCase(roleNames)(parts[1]))
+	d.Set("restore_on_destroy", false)
+	d.Set("reset_to_defaults", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceRoleSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
 	roleName := d.Get("role_name").(string)
@@ -97,15 +475,29 @@ func resourceRoleSettingsCreate(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 
-	existingRoleSettings, err := client.GetRoleSettings(objectId, roleDefinition.ID)
+	if d.IsNewResource() {
+		existingRoleSettings, err := client.GetRoleSettings(objectId, roleDefinition.ID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		originalRoleSettings, err := json.Marshal(existingRoleSettings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("original_settings_json", string(originalRoleSettings))
+	}
+
+	updatedRoleSettings, err := getPlannedRoleSettings(client, d, objectId, roleDefinition.ID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	updatedRoleSettings, err := createUpdatedRoleSettings(existingRoleSettings, d)
+	plannedPayload, err := json.Marshal(updatedRoleSettings)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	d.Set("planned_payload_json", string(plannedPayload))
 
 	err = client.UpdateRoleSettings(updatedRoleSettings)
 	if err != nil {
@@ -117,7 +509,7 @@ func resourceRoleSettingsCreate(ctx context.Context, d *schema.ResourceData, met
 
 func resourceRoleSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := meta.(*azurepag.Client)
+	client := meta.(*Client)
 
 	objectId := d.Get("object_id").(string)
 	roleName := d.Get("role_name").(string)
@@ -137,12 +529,22 @@ func resourceRoleSettingsRead(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	d.Set("allow_permanent_eligible_assignments", roleSettingsOptions.AllowPermanentEligibleAssignments)
-	d.Set("max_eligible_assignment_time_mins", roleSettingsOptions.MaxEligibleAssignmentTimeMins)
-	d.Set("max_activation_time_mins", roleSettingsOptions.MaxActivationTimeMins)
-	d.Set("require_justification_on_activation", roleSettingsOptions.RequireJustificationOnActivation)
-	d.Set("require_mfa_on_activation", roleSettingsOptions.RequireMFAOnActivation)
-	d.Set("require_ticket_info_on_activation", roleSettingsOptions.RequireTicketInfoOnActivation)
+	d.Set("activation", flattenRoleSettingsActivation(roleSettingsOptions, flattenRoleSettingsDuration(d.Get("activation.0.max_duration").(string), roleSettingsOptions.MaxActivationTimeMins)))
+	d.Set("eligible_assignment", flattenRoleSettingsEligibleAssignment(roleSettingsOptions, flattenRoleSettingsDuration(d.Get("eligible_assignment.0.max_duration").(string), roleSettingsOptions.MaxEligibleAssignmentTimeMins)))
+	d.Set("active_assignment", flattenRoleSettingsActiveAssignment(roleSettingsOptions, flattenRoleSettingsDuration(d.Get("active_assignment.0.max_duration").(string), roleSettingsOptions.MaxActiveAssignmentTimeMins)))
+	d.Set("notification", flattenRoleSettingsNotifications(filterRoleSettingsNotifications(roleSettingsOptions.Notifications, d.Get("notification").(*schema.Set).List())))
+	additionalRules, err := flattenRoleSettingsAdditionalRules(roleSettings, d.Get("additional_rule").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("additional_rule", additionalRules)
+
+	rawSettings, err := json.Marshal(roleSettings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("raw_settings_json", string(rawSettings))
+
 	d.Set("role_definition_id", roleDefinition.ID)
 	d.SetId(roleSettings.ID)
 
@@ -150,140 +552,705 @@ func resourceRoleSettingsRead(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceRoleSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Changes to restore_on_destroy or reset_to_defaults alone only affect destroy.
+	if !d.HasChanges(roleSettingsManagedAttributes...) {
+		return resourceRoleSettingsRead(ctx, d, meta)
+	}
+
 	return resourceRoleSettingsCreate(ctx, d, meta)
 }
 
+// getPlannedRoleSettings builds the document to send from the live settings. If the plan showed a
+// planned_payload_json, the document must still match it, otherwise the settings were edited in PIM after the
+// plan and sending either document would overwrite changes nobody reviewed.
+func getPlannedRoleSettings(client *Client, d *schema.ResourceData, objectId string, roleDefinitionId string) (*azurepag.RoleSettings, error) {
+	roleSettings, err := client.GetRoleSettings(objectId, roleDefinitionId)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRoleSettings, err := createUpdatedRoleSettings(roleSettings, d)
+	if err != nil {
+		return nil, err
+	}
+
+	plannedPayload := d.Get("planned_payload_json").(string)
+	if plannedPayload == "" {
+		return updatedRoleSettings, nil
+	}
+
+	payload, err := json.Marshal(updatedRoleSettings)
+	if err != nil {
+		return nil, err
+	}
+	if !structure.SuppressJsonDiff("planned_payload_json", plannedPayload, string(payload), d) {
+		return nil, fmt.Errorf("The role settings of %s changed in PIM after the plan was made, run plan again to review the new payload.", objectId)
+	}
+	return updatedRoleSettings, nil
+}
+
 func resourceRoleSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
+	client := meta.(*Client)
+
+	if d.Get("restore_on_destroy").(bool) {
+		originalRoleSettingsJson := d.Get("original_settings_json").(string)
+		if originalRoleSettingsJson == "" {
+			return diag.Errorf("There is no snapshot of the original role settings of %s to restore, e.g. because they were imported. Set restore_on_destroy to false or use reset_to_defaults instead.", d.Id())
+		}
+
+		originalRoleSettings := azurepag.RoleSettings{}
+		err := json.Unmarshal([]byte(originalRoleSettingsJson), &originalRoleSettings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.UpdateRoleSettings(&originalRoleSettings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else if d.Get("reset_to_defaults").(bool) {
+		roleSettings, err := client.GetRoleSettings(d.Get("object_id").(string), d.Get("role_definition_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		defaultRoleSettings, err := mergeRoleSettings(roleSettings, &defaultRoleSettingsOptions)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.UpdateRoleSettings(defaultRoleSettings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	// TODO: Should we restore original settings ?
 	d.SetId("")
 
 	return diags
 }
 
-func createUpdatedRoleSettings(roleSettings *azurepag.RoleSettings, d *schema.ResourceData) (*azurepag.RoleSettings, error) {
+type roleSettingsDurationLimit struct {
+	Block string
+	Min   time.Duration
+	Max   time.Duration
+	Step  time.Duration
+}
+
+// roleSettingsDurationLimits are the ranges and increments PIM accepts for each block's maximum duration.
+var roleSettingsDurationLimits = []roleSettingsDurationLimit{
+	{Block: "activation", Min: 30 * time.Minute, Max: 24 * time.Hour, Step: 30 * time.Minute},
+	{Block: "eligible_assignment", Min: 24 * time.Hour, Max: 365 * 24 * time.Hour, Step: 24 * time.Hour},
+	{Block: "active_assignment", Min: 24 * time.Hour, Max: 365 * 24 * time.Hour, Step: 24 * time.Hour},
+}
+
+// resourceRoleSettingsCustomizeDiff rejects combinations PIM would answer with a 400 before anything is sent.
+func resourceRoleSettingsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, limit := range roleSettingsDurationLimits {
+		err := validateRoleSettingsDuration(d, limit)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, block := range []string{"eligible_assignment", "active_assignment"} {
+		err := validateRoleSettingsExpiration(d, block)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isRoleSettingsValueKnown(d, "activation.0.require_approval") && d.Get("activation.0.require_approval").(bool) && d.Get("activation.0.approver").(*schema.Set).Len() == 0 {
+		return errors.New("activation.0.require_approval is true, so at least one activation.0.approver must be set")
+	}
+
+	// Only creating the resource takes a snapshot, so existing settings without one have nothing to restore.
+	if d.Id() != "" && !d.HasChanges("object_id", "role_name") && d.Get("restore_on_destroy").(bool) && d.Get("original_settings_json").(string) == "" {
+		return errors.New("restore_on_destroy cannot be enabled because there is no snapshot of the original role settings, e.g. because they were imported")
+	}
+
+	err := checkRoleSettingsAuthenticationContext(d.GetRawConfig())
+	if err != nil {
+		return err
+	}
+
+	err = validateRoleSettingsAdditionalRules(d)
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range roleSettingsDurationLimits {
+		err := setRoleSettingsDurationMinutes(d, limit.Block)
+		if err != nil {
+			return err
+		}
+	}
+
+	return setPlannedRoleSettingsPayload(d, meta)
+}
+
+// roleSettingsManagedAttributes are the attributes that change the role settings document sent to PIM.
+var roleSettingsManagedAttributes = []string{"activation", "eligible_assignment", "active_assignment", "notification", "additional_rule"}
+
+// setPlannedRoleSettingsPayload fetches the role's current settings and plans the document the next apply
+// sends, so it can be reviewed before it reaches PIM. When the settings cannot be read yet, e.g. because the
+// group is registered in the same apply, the payload is left unknown and built at apply.
+func setPlannedRoleSettingsPayload(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		if !d.HasChanges(roleSettingsManagedAttributes...) {
+			return nil
+		}
+
+		// The settings read back after apply differ from the current ones.
+		err := d.SetNewComputed("raw_settings_json")
+		if err != nil {
+			return err
+		}
+	}
+
+	client, ok := meta.(*Client)
+	if !ok || !d.NewValueKnown("object_id") || !d.NewValueKnown("role_name") {
+		return d.SetNewComputed("planned_payload_json")
+	}
+
+	objectId := d.Get("object_id").(string)
+	roleName := d.Get("role_name").(string)
+
+	roleDefinition, err := findRoleDefinition(client, objectId, roleName)
+	if err != nil {
+		log.Printf("[DEBUG] Not planning the role settings payload of %s on group %s: %s", roleName, objectId, err)
+		return d.SetNewComputed("planned_payload_json")
+	}
+
+	roleSettings, err := client.GetRoleSettings(objectId, roleDefinition.ID)
+	if err != nil {
+		log.Printf("[DEBUG] Not planning the role settings payload of %s on group %s: %s", roleName, objectId, err)
+		return d.SetNewComputed("planned_payload_json")
+	}
+
+	updatedRoleSettings, err := createUpdatedRoleSettings(roleSettings, d)
+	if err != nil {
+		return err
+	}
+
+	plannedPayload, err := json.Marshal(updatedRoleSettings)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("planned_payload_json", string(plannedPayload))
+}
+
+// isRoleSettingsValueKnown reports whether a block attribute has a value yet. Blocks left out of the
+// configuration of a new resource are only filled in by the first read.
+func isRoleSettingsValueKnown(d *schema.ResourceDiff, key string) bool {
+	block := strings.SplitN(key, ".", 2)[0]
+	return len(d.Get(block).([]interface{})) > 0 && d.NewValueKnown(key)
+}
+
+// validateRoleSettingsExpiration requires a maximum duration for assignments that may not be permanent.
+func validateRoleSettingsExpiration(d *schema.ResourceDiff, block string) error {
+	key := block + ".0.allow_permanent"
+	if !isRoleSettingsValueKnown(d, key) || d.Get(key).(bool) {
+		return nil
+	}
+
+	if d.Get(block+".0.max_duration").(string) != "" || d.Get(block+".0.max_duration_mins").(int) > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s is false, so %s.0.max_duration or %s.0.max_duration_mins must be set", key, block, block)
+}
+
+// validateRoleSettingsDuration checks a changed maximum duration against PIM's limits, so that values PIM
+// would reject fail at plan time. Durations already in place are not checked again.
+func validateRoleSettingsDuration(d *schema.ResourceDiff, limit roleSettingsDurationLimit) error {
+	key := limit.Block + ".0.max_duration"
+
+	var duration time.Duration
+	if v := d.Get(key).(string); v != "" && d.HasChange(key) {
+		parsed, err := parseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %s", key, err)
+		}
+		duration = parsed
+	} else if d.HasChange(key + "_mins") {
+		key += "_mins"
+		duration = time.Duration(d.Get(key).(int)) * time.Minute
+	}
+
+	if duration == 0 {
+		return nil
+	}
+	return checkRoleSettingsDuration(key, duration, limit)
+}
+
+// setRoleSettingsDurationMinutes plans max_duration_mins from a changed max_duration, so the plan does not keep
+// showing the old minutes. SetNew only accepts top-level keys, so the whole block is planned.
+func setRoleSettingsDurationMinutes(d *schema.ResourceDiff, block string) error {
+	key := block + ".0.max_duration"
+	if !d.HasChange(key) || !isRoleSettingsValueKnown(d, key) {
+		return nil
+	}
+	v := d.Get(key).(string)
+	if v == "" {
+		return nil
+	}
+
+	attributes := d.Get(block).([]interface{})[0].(map[string]interface{})
+	result := make(map[string]interface{}, len(attributes))
+	for attribute, value := range attributes {
+		if !d.NewValueKnown(block + ".0." + attribute) {
+			return d.SetNewComputed(block)
+		}
+		result[attribute] = value
+	}
+	result["max_duration_mins"] = getDurationMinutes(v)
+
+	return d.SetNew(block, []interface{}{result})
+}
+
+func checkRoleSettingsDuration(key string, duration time.Duration, limit roleSettingsDurationLimit) error {
+	if duration < limit.Min || duration > limit.Max || duration%limit.Step != 0 {
+		return fmt.Errorf("%s must be between %s and %s in steps of %s, got %s", key, limit.Min, limit.Max, limit.Step, duration)
+	}
+	return nil
+}
+
+// flattenRoleSettingsDuration keeps the configured duration string while it still matches the role's
+// settings, and otherwise reports the current value so the difference shows up in the plan.
+func flattenRoleSettingsDuration(current string, minutes int) string {
+	if current == "" {
+		return ""
+	}
+	if duration, err := parseDuration(current); err == nil && duration == time.Duration(minutes)*time.Minute {
+		return current
+	}
+	return formatDurationMinutes(minutes)
+}
+
+// getDurationMinutes converts a duration validated by validateDuration to whole minutes.
+func getDurationMinutes(v string) int {
+	duration, _ := parseDuration(v)
+	return int(duration / time.Minute)
+}
+
+// roleSettingsChanges is implemented by both schema.ResourceData and schema.ResourceDiff, so the settings
+// sent on apply can also be planned.
+type roleSettingsChanges interface {
+	Get(key string) interface{}
+	HasChange(key string) bool
+}
+
+func createUpdatedRoleSettings(roleSettings *azurepag.RoleSettings, d roleSettingsChanges) (*azurepag.RoleSettings, error) {
 	roleSettingsOptions, err := getRoleSettingsOptions(roleSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	assignmentExpirationRuleSetting, err := json.Marshal(azurepag.RoleSettingsExpirationRuleSetting{
-		PermanentAssignment: func() bool {
-			if d.HasChange("allow_permanent_eligible_assignments") {
-				return d.Get("allow_permanent_eligible_assignments").(bool)
-			} else {
-				return roleSettingsOptions.AllowPermanentEligibleAssignments
-			}
-		}(),
-		MaximumGrantPeriodInMinutes: func() int {
-			if d.HasChange("max_eligible_assignment_time_mins") {
-				return d.Get("max_eligible_assignment_time_mins").(int)
-			} else {
-				return roleSettingsOptions.MaxEligibleAssignmentTimeMins
-			}
-		}(),
+	if d.HasChange("activation.0.max_duration_mins") {
+		roleSettingsOptions.MaxActivationTimeMins = d.Get("activation.0.max_duration_mins").(int)
+	}
+	if v := d.Get("activation.0.max_duration").(string); v != "" && d.HasChange("activation.0.max_duration") {
+		roleSettingsOptions.MaxActivationTimeMins = getDurationMinutes(v)
+	}
+	if d.HasChange("activation.0.require_mfa") {
+		roleSettingsOptions.RequireMFAOnActivation = d.Get("activation.0.require_mfa").(bool)
+	}
+	if d.HasChange("activation.0.authentication_context") {
+		roleSettingsOptions.ActivationAuthenticationContext = d.Get("activation.0.authentication_context").(string)
+	}
+	if d.HasChange("activation.0.require_justification") {
+		roleSettingsOptions.RequireJustificationOnActivation = d.Get("activation.0.require_justification").(bool)
+	}
+	if d.HasChange("activation.0.require_ticket_info") {
+		roleSettingsOptions.RequireTicketInfoOnActivation = d.Get("activation.0.require_ticket_info").(bool)
+	}
+	if d.HasChange("activation.0.require_approval") {
+		roleSettingsOptions.RequireApprovalOnActivation = d.Get("activation.0.require_approval").(bool)
+	}
+	if d.HasChange("activation.0.approver") {
+		roleSettingsOptions.Approvers = expandRoleSettingsApprovers(d.Get("activation.0.approver").(*schema.Set).List())
+	}
+	if d.HasChange("eligible_assignment.0.allow_permanent") {
+		roleSettingsOptions.AllowPermanentEligibleAssignments = d.Get("eligible_assignment.0.allow_permanent").(bool)
+	}
+	if d.HasChange("eligible_assignment.0.max_duration_mins") {
+		roleSettingsOptions.MaxEligibleAssignmentTimeMins = d.Get("eligible_assignment.0.max_duration_mins").(int)
+	}
+	if v := d.Get("eligible_assignment.0.max_duration").(string); v != "" && d.HasChange("eligible_assignment.0.max_duration") {
+		roleSettingsOptions.MaxEligibleAssignmentTimeMins = getDurationMinutes(v)
+	}
+	if d.HasChange("active_assignment.0.allow_permanent") {
+		roleSettingsOptions.AllowPermanentActiveAssignments = d.Get("active_assignment.0.allow_permanent").(bool)
+	}
+	if d.HasChange("active_assignment.0.max_duration_mins") {
+		roleSettingsOptions.MaxActiveAssignmentTimeMins = d.Get("active_assignment.0.max_duration_mins").(int)
+	}
+	if v := d.Get("active_assignment.0.max_duration").(string); v != "" && d.HasChange("active_assignment.0.max_duration") {
+		roleSettingsOptions.MaxActiveAssignmentTimeMins = getDurationMinutes(v)
+	}
+	if d.HasChange("active_assignment.0.require_mfa") {
+		roleSettingsOptions.RequireMFAOnActiveAssignment = d.Get("active_assignment.0.require_mfa").(bool)
+	}
+	if d.HasChange("active_assignment.0.require_justification") {
+		roleSettingsOptions.RequireJustificationOnActiveAssignment = d.Get("active_assignment.0.require_justification").(bool)
+	}
+	// Additional rules are applied every time, so changes made outside Terraform are reverted.
+	additionalRules, err := expandRoleSettingsAdditionalRules(d.Get("additional_rule").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	roleSettingsOptions.AdditionalRules = additionalRules
+
+	if d.HasChange("notification") {
+		roleSettingsOptions.Notifications = mergeRoleSettingsNotifications(
+			roleSettingsOptions.Notifications,
+			expandRoleSettingsNotifications(d.Get("notification").(*schema.Set).List()),
+		)
+	}
+
+	return mergeRoleSettings(roleSettings, roleSettingsOptions)
+}
+
+// mergeRoleSettings writes the modelled options into a copy of the role's current settings. Lifecycle
+// entries, rules and rule properties the provider does not manage are passed through unchanged.
+func mergeRoleSettings(roleSettings *azurepag.RoleSettings, roleSettingsOptions *RoleSettingsOptions) (*azurepag.RoleSettings, error) {
+	result := azurepag.RoleSettings{
+		ID:                  roleSettings.ID,
+		ResourceID:          roleSettings.ResourceID,
+		RoleDefinitionID:    roleSettings.RoleDefinitionID,
+		LifecycleManagement: make([]azurepag.LifecycleManagement, len(roleSettings.LifecycleManagement)),
+	}
+	for i, item := range roleSettings.LifecycleManagement {
+		item.RoleSettingsRules = append([]azurepag.RoleSettingsRule{}, item.RoleSettingsRules...)
+		result.LifecycleManagement[i] = item
+	}
+
+	err := setRoleSettingsRule(&result, "Admin", "Eligible", "ExpirationRule", newExpirationRuleSetting(
+		roleSettingsOptions.AllowPermanentEligibleAssignments,
+		roleSettingsOptions.MaxEligibleAssignmentTimeMins,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	err = setRoleSettingsRule(&result, "Admin", "Member", "ExpirationRule", newExpirationRuleSetting(
+		roleSettingsOptions.AllowPermanentActiveAssignments,
+		roleSettingsOptions.MaxActiveAssignmentTimeMins,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	err = setRoleSettingsRule(&result, "Admin", "Member", "MfaRule", azurepag.RoleSettingsMfaRuleSetting{
+		MFARequired: roleSettingsOptions.RequireMFAOnActiveAssignment,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	expirationRuleSetting, err := json.Marshal(azurepag.RoleSettingsExpirationRuleSetting{
-		PermanentAssignment: true, // This property is unused but must be specified
-		MaximumGrantPeriodInMinutes: func() int {
-			if d.HasChange("max_activation_time_mins") {
-				return d.Get("max_activation_time_mins").(int)
-			} else {
-				return roleSettingsOptions.MaxActivationTimeMins
-			}
-		}(),
+	err = setRoleSettingsRule(&result, "Admin", "Member", "JustificationRule", azurepag.RoleSettingsJustificationRuleSetting{
+		Required: roleSettingsOptions.RequireJustificationOnActiveAssignment,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	mfaRuleSetting, err := json.Marshal(azurepag.RoleSettingsMfaRuleSetting{
-		MFARequired: func() bool {
-			if d.HasChange("require_mfa_on_activation") {
-				return d.Get("require_mfa_on_activation").(bool)
-			} else {
-				return roleSettingsOptions.RequireMFAOnActivation
-			}
-		}(),
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "ExpirationRule", newExpirationRuleSetting(
+		true, // This property is unused but must be specified
+		roleSettingsOptions.MaxActivationTimeMins,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	// PIM rejects the MFA rule together with an authentication context, which can require MFA itself.
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "MfaRule", azurepag.RoleSettingsMfaRuleSetting{
+		MFARequired: roleSettingsOptions.RequireMFAOnActivation && roleSettingsOptions.ActivationAuthenticationContext == "",
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	justificationRuleSetting, err := json.Marshal(azurepag.RoleSettingsJustificationRuleSetting{
-		Required: func() bool {
-			if d.HasChange("require_justification_on_activation") {
-				return d.Get("require_justification_on_activation").(bool)
-			} else {
-				return roleSettingsOptions.RequireJustificationOnActivation
-			}
-		}(),
+	acrsRuleSetting := RoleSettingsAcrsRuleSetting{}
+	if roleSettingsOptions.ActivationAuthenticationContext != "" {
+		acrsRuleSetting.AcrsRequired = true
+		acrsRuleSetting.Acrs = &roleSettingsOptions.ActivationAuthenticationContext
+	}
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "AcrsRule", acrsRuleSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "JustificationRule", azurepag.RoleSettingsJustificationRuleSetting{
+		Required: roleSettingsOptions.RequireJustificationOnActivation,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	ticketInfoRuleSetting, err := json.Marshal(azurepag.RoleSettingsTicketingRuleSetting{
-		TicketingRequired: func() bool {
-			if d.HasChange("require_ticket_info_on_activation") {
-				return d.Get("require_ticket_info_on_activation").(bool)
-			} else {
-				return roleSettingsOptions.RequireTicketInfoOnActivation
-			}
-		}(),
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "TicketingRule", azurepag.RoleSettingsTicketingRuleSetting{
+		TicketingRequired: roleSettingsOptions.RequireTicketInfoOnActivation,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	result := azurepag.RoleSettings{
-		ID: roleSettings.ID,
-		LifecycleManagement: []azurepag.LifecycleManagement{
-			{
-				Caller:    "EndUser",
-				Level:     "Member",
-				Operation: "ALL",
-				RoleSettingsRules: []azurepag.RoleSettingsRule{
-					{
-						RuleIdentifier: "ExpirationRule",
-						Setting:        string(expirationRuleSetting),
-					},
-					{
-						RuleIdentifier: "MfaRule",
-						Setting:        string(mfaRuleSetting),
-					},
-					{
-						RuleIdentifier: "JustificationRule",
-						Setting:        string(justificationRuleSetting),
-					},
-					{
-						RuleIdentifier: "TicketingRule",
-						Setting:        string(ticketInfoRuleSetting),
-					},
-				},
-			},
-			{
-				Caller:    "Admin",
-				Level:     "Eligible",
-				Operation: "ALL",
-				RoleSettingsRules: []azurepag.RoleSettingsRule{
-					{
-						RuleIdentifier: "ExpirationRule",
-						Setting:        string(assignmentExpirationRuleSetting),
-					},
-				},
-			},
-		},
+	err = setRoleSettingsRule(&result, "EndUser", "Member", "ApprovalRule", RoleSettingsApprovalRuleSetting{
+		Enabled:   roleSettingsOptions.RequireApprovalOnActivation,
+		Approvers: roleSettingsOptions.Approvers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, notificationType := range roleSettingsNotificationTypes {
+		err = setNotificationRule(&result, notificationType, roleSettingsOptions.Notifications)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range roleSettingsOptions.AdditionalRules {
+		err = setRoleSettingsOperationRule(&result, rule.Caller, rule.Level, rule.Operation, rule.RuleIdentifier, rule.Setting)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &result, nil
 }
 
+// setNotificationRule writes the email notifications of one type into its NotificationRule. Entries for
+// recipient types without a notification are kept as they are.
+func setNotificationRule(roleSettings *azurepag.RoleSettings, notificationType string, notifications []RoleSettingsNotification) error {
+	caller, level := roleSettingsNotificationLifecycles[notificationType][0], roleSettingsNotificationLifecycles[notificationType][1]
+
+	// The rule is edited as raw JSON, so properties and policies this provider does not model are kept.
+	setting := map[string]interface{}{}
+	if lifecycle := findLifecycleManagement(roleSettings, caller, level, "ALL"); lifecycle != nil {
+		err := unmarshalOptionalRuleSetting(lifecycle.RoleSettingsRules, "NotificationRule", &setting)
+		if err != nil {
+			return err
+		}
+	}
+	if setting == nil {
+		setting = map[string]interface{}{}
+	}
+
+	changed := false
+	policy := findEmailNotificationPolicy(setting)
+	policySettings, _ := policy["setting"].([]interface{})
+	for _, notification := range notifications {
+		if notification.Type != notificationType {
+			continue
+		}
+		changed = true
+
+		policySetting := RoleSettingsNotificationPolicySetting{
+			IsDefaultReceiverEnabled: notification.DefaultRecipients,
+			NotificationLevel:        notificationLevelAll,
+			RecipientType:            indexOfString(roleSettingsNotificationRecipientTypes, notification.RecipientType),
+		}
+		if len(notification.AdditionalRecipients) > 0 {
+			policySetting.CustomReceivers = notification.AdditionalRecipients
+		}
+		if notification.CriticalOnly {
+			policySetting.NotificationLevel = notificationLevelCritical
+		}
+
+		rb, err := json.Marshal(policySetting)
+		if err != nil {
+			return err
+		}
+		values := map[string]interface{}{}
+		err = json.Unmarshal(rb, &values)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, item := range policySettings {
+			existing, ok := item.(map[string]interface{})
+			if !ok || existing["recipienttype"] != values["recipienttype"] {
+				continue
+			}
+			for key, value := range values {
+				existing[key] = value
+			}
+			found = true
+		}
+		if !found {
+			policySettings = append(policySettings, values)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	policy["setting"] = policySettings
+
+	return setRoleSettingsRule(roleSettings, caller, level, "NotificationRule", setting)
+}
+
+// findEmailNotificationPolicy returns the email policy of a raw NotificationRule setting, adding it if missing.
+func findEmailNotificationPolicy(setting map[string]interface{}) map[string]interface{} {
+	policies, _ := setting["policies"].([]interface{})
+	for _, item := range policies {
+		if policy, ok := item.(map[string]interface{}); ok && policy["deliveryMechanism"] == "email" {
+			return policy
+		}
+	}
+
+	policy := map[string]interface{}{"deliveryMechanism": "email"}
+	setting["policies"] = append(policies, policy)
+	return policy
+}
+
+func getRoleSettingsNotifications(roleSettings *azurepag.RoleSettings) ([]RoleSettingsNotification, error) {
+	result := []RoleSettingsNotification{}
+	for _, notificationType := range roleSettingsNotificationTypes {
+		lifecycle := findLifecycleManagement(roleSettings, roleSettingsNotificationLifecycles[notificationType][0], roleSettingsNotificationLifecycles[notificationType][1], "ALL")
+		if lifecycle == nil {
+			continue
+		}
+
+		setting := RoleSettingsNotificationRuleSetting{}
+		err := unmarshalOptionalRuleSetting(lifecycle.RoleSettingsRules, "NotificationRule", &setting)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, policy := range setting.Policies {
+			if policy.DeliveryMechanism != "email" {
+				continue
+			}
+			for _, item := range policy.Setting {
+				if item.RecipientType < 0 || item.RecipientType >= len(roleSettingsNotificationRecipientTypes) {
+					continue
+				}
+				additionalRecipients := item.CustomReceivers
+				if additionalRecipients == nil {
+					additionalRecipients = []string{}
+				}
+				result = append(result, RoleSettingsNotification{
+					Type:                 notificationType,
+					RecipientType:        roleSettingsNotificationRecipientTypes[item.RecipientType],
+					DefaultRecipients:    item.IsDefaultReceiverEnabled,
+					AdditionalRecipients: additionalRecipients,
+					CriticalOnly:         item.NotificationLevel == notificationLevelCritical,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// mergeRoleSettingsNotifications replaces the current notifications with the configured ones of the same
+// type and recipient type.
+func mergeRoleSettingsNotifications(current []RoleSettingsNotification, configured []RoleSettingsNotification) []RoleSettingsNotification {
+	result := []RoleSettingsNotification{}
+	for _, item := range current {
+		if findRoleSettingsNotification(configured, item.Type, item.RecipientType) == nil {
+			result = append(result, item)
+		}
+	}
+	return append(result, configured...)
+}
+
+func findRoleSettingsNotification(notifications []RoleSettingsNotification, notificationType string, recipientType string) *RoleSettingsNotification {
+	for i, item := range notifications {
+		if item.Type == notificationType && item.RecipientType == recipientType {
+			return &notifications[i]
+		}
+	}
+	return nil
+}
+
+func newExpirationRuleSetting(permanentAssignment bool, maximumGrantPeriodInMinutes int) RoleSettingsExpirationRuleSetting {
+	minutes := maximumGrantPeriodInMinutes % 60
+	hours := maximumGrantPeriodInMinutes / 60 % 24
+	days := maximumGrantPeriodInMinutes / 60 / 24
+
+	maximumGrantPeriod := fmt.Sprintf("%02d:%02d:00", hours, minutes)
+	if days > 0 {
+		maximumGrantPeriod = fmt.Sprintf("%d.%s", days, maximumGrantPeriod)
+	}
+
+	return RoleSettingsExpirationRuleSetting{
+		RoleSettingsExpirationRuleSetting: azurepag.RoleSettingsExpirationRuleSetting{
+			PermanentAssignment:         permanentAssignment,
+			MaximumGrantPeriodInMinutes: maximumGrantPeriodInMinutes,
+		},
+		MaximumGrantPeriod: maximumGrantPeriod,
+	}
+}
+
+// setRoleSettingsRule overlays setting onto the rule with the given identifier in the Caller/Level entry,
+// keeping properties of the existing rule that setting does not cover. Missing entries and rules are added.
+func setRoleSettingsRule(roleSettings *azurepag.RoleSettings, caller string, level string, ruleIdentifier string, setting interface{}) error {
+	return setRoleSettingsOperationRule(roleSettings, caller, level, "ALL", ruleIdentifier, setting)
+}
+
+func setRoleSettingsOperationRule(roleSettings *azurepag.RoleSettings, caller string, level string, operation string, ruleIdentifier string, setting interface{}) error {
+	rb, err := json.Marshal(setting)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{}
+	err = json.Unmarshal(rb, &values)
+	if err != nil {
+		return err
+	}
+
+	lifecycle := findLifecycleManagement(roleSettings, caller, level, operation)
+	if lifecycle == nil {
+		roleSettings.LifecycleManagement = append(roleSettings.LifecycleManagement, azurepag.LifecycleManagement{
+			Caller:    caller,
+			Level:     level,
+			Operation: operation,
+		})
+		lifecycle = &roleSettings.LifecycleManagement[len(roleSettings.LifecycleManagement)-1]
+	}
+
+	for i, rule := range lifecycle.RoleSettingsRules {
+		if rule.RuleIdentifier != ruleIdentifier {
+			continue
+		}
+
+		existing := map[string]interface{}{}
+		if json.Unmarshal([]byte(rule.Setting), &existing) != nil {
+			existing = map[string]interface{}{}
+		}
+		for key, value := range values {
+			existing[key] = value
+		}
+
+		merged, err := json.Marshal(existing)
+		if err != nil {
+			return err
+		}
+		lifecycle.RoleSettingsRules[i].Setting = string(merged)
+		return nil
+	}
+
+	lifecycle.RoleSettingsRules = append(lifecycle.RoleSettingsRules, azurepag.RoleSettingsRule{
+		RuleIdentifier: ruleIdentifier,
+		Setting:        string(rb),
+	})
+	return nil
+}
+
+func findLifecycleManagement(roleSettings *azurepag.RoleSettings, caller string, level string, operation string) *azurepag.LifecycleManagement {
+	for i, item := range roleSettings.LifecycleManagement {
+		if item.Caller == caller && item.Level == level && item.Operation == operation {
+			return &roleSettings.LifecycleManagement[i]
+		}
+	}
+	return nil
+}
+
 func getRoleSettingsOptions(roleSettings *azurepag.RoleSettings) (*RoleSettingsOptions, error) {
 	activationRules, err := getActivationRules(roleSettings)
 	if err != nil {
@@ -295,6 +1262,11 @@ func getRoleSettingsOptions(roleSettings *azurepag.RoleSettings) (*RoleSettingsO
 		return nil, err
 	}
 
+	activeAssignmentRules, err := getActiveAssignmentRules(roleSettings)
+	if err != nil {
+		return nil, err
+	}
+
 	ticketingRule, err := getRuleSetting(activationRules.RoleSettingsRules, "TicketingRule")
 	if err != nil {
 		return nil, err
@@ -350,13 +1322,79 @@ func getRoleSettingsOptions(roleSettings *azurepag.RoleSettings) (*RoleSettingsO
 		return nil, err
 	}
 
+	activeAssignmentExpirationRule, err := getRuleSetting(activeAssignmentRules.RoleSettingsRules, "ExpirationRule")
+	if err != nil {
+		return nil, err
+	}
+
+	activeAssignmentExpirationRuleSettings := azurepag.RoleSettingsExpirationRuleSetting{}
+	err = json.Unmarshal([]byte(activeAssignmentExpirationRule.Setting), &activeAssignmentExpirationRuleSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	activeAssignmentMfaRule, err := getRuleSetting(activeAssignmentRules.RoleSettingsRules, "MfaRule")
+	if err != nil {
+		return nil, err
+	}
+
+	activeAssignmentMfaRuleSetting := azurepag.RoleSettingsMfaRuleSetting{}
+	err = json.Unmarshal([]byte(activeAssignmentMfaRule.Setting), &activeAssignmentMfaRuleSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	activeAssignmentJustificationRule, err := getRuleSetting(activeAssignmentRules.RoleSettingsRules, "JustificationRule")
+	if err != nil {
+		return nil, err
+	}
+
+	activeAssignmentJustificationRuleSetting := azurepag.RoleSettingsJustificationRuleSetting{}
+	err = json.Unmarshal([]byte(activeAssignmentJustificationRule.Setting), &activeAssignmentJustificationRuleSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	acrsRuleSetting := RoleSettingsAcrsRuleSetting{}
+	err = unmarshalOptionalRuleSetting(activationRules.RoleSettingsRules, "AcrsRule", &acrsRuleSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	activationAuthenticationContext := ""
+	if acrsRuleSetting.AcrsRequired && acrsRuleSetting.Acrs != nil {
+		activationAuthenticationContext = *acrsRuleSetting.Acrs
+	}
+
+	notifications, err := getRoleSettingsNotifications(roleSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	approvalRuleSetting := RoleSettingsApprovalRuleSetting{}
+	err = unmarshalOptionalRuleSetting(activationRules.RoleSettingsRules, "ApprovalRule", &approvalRuleSetting)
+	if err != nil {
+		return nil, err
+	}
+	if approvalRuleSetting.Approvers == nil {
+		approvalRuleSetting.Approvers = []RoleSettingsApprover{}
+	}
+
 	result := RoleSettingsOptions{
-		MaxEligibleAssignmentTimeMins:     assignmentExpirationRuleSettings.MaximumGrantPeriodInMinutes,
-		AllowPermanentEligibleAssignments: assignmentExpirationRuleSettings.PermanentAssignment,
-		MaxActivationTimeMins:             expirationRuleSettings.MaximumGrantPeriodInMinutes,
-		RequireMFAOnActivation:            mfaRuleSetting.MFARequired,
-		RequireJustificationOnActivation:  justificationRuleSetting.Required,
-		RequireTicketInfoOnActivation:     ticketingRuleSetting.TicketingRequired,
+		MaxEligibleAssignmentTimeMins:          assignmentExpirationRuleSettings.MaximumGrantPeriodInMinutes,
+		AllowPermanentEligibleAssignments:      assignmentExpirationRuleSettings.PermanentAssignment,
+		MaxActivationTimeMins:                  expirationRuleSettings.MaximumGrantPeriodInMinutes,
+		RequireMFAOnActivation:                 mfaRuleSetting.MFARequired,
+		RequireJustificationOnActivation:       justificationRuleSetting.Required,
+		RequireTicketInfoOnActivation:          ticketingRuleSetting.TicketingRequired,
+		RequireApprovalOnActivation:            approvalRuleSetting.Enabled,
+		Approvers:                              approvalRuleSetting.Approvers,
+		AllowPermanentActiveAssignments:        activeAssignmentExpirationRuleSettings.PermanentAssignment,
+		MaxActiveAssignmentTimeMins:            activeAssignmentExpirationRuleSettings.MaximumGrantPeriodInMinutes,
+		RequireMFAOnActiveAssignment:           activeAssignmentMfaRuleSetting.MFARequired,
+		RequireJustificationOnActiveAssignment: activeAssignmentJustificationRuleSetting.Required,
+		Notifications:                          notifications,
+		ActivationAuthenticationContext:        activationAuthenticationContext,
 	}
 
 	return &result, nil
@@ -371,6 +1409,235 @@ func getRuleSetting(rules []azurepag.RoleSettingsRule, ruleIdentifier string) (*
 	return nil, errors.New("Role activation rules not found.")
 }
 
+// unmarshalOptionalRuleSetting decodes the setting of a rule PIM may omit, leaving v untouched when it is absent.
+func unmarshalOptionalRuleSetting(rules []azurepag.RoleSettingsRule, ruleIdentifier string, v interface{}) error {
+	for _, item := range rules {
+		if item.RuleIdentifier == ruleIdentifier {
+			return json.Unmarshal([]byte(item.Setting), v)
+		}
+	}
+	return nil
+}
+
+func expandRoleSettingsApprovers(input []interface{}) []RoleSettingsApprover {
+	result := make([]RoleSettingsApprover, 0, len(input))
+	for _, item := range input {
+		approver := item.(map[string]interface{})
+		result = append(result, RoleSettingsApprover{
+			ID:   approver["object_id"].(string),
+			Type: approver["type"].(string),
+		})
+	}
+	return result
+}
+
+func flattenRoleSettingsApprovers(approvers []RoleSettingsApprover) []interface{} {
+	result := make([]interface{}, 0, len(approvers))
+	for _, item := range approvers {
+		result = append(result, map[string]interface{}{
+			"object_id": item.ID,
+			"type":      item.Type,
+		})
+	}
+	return result
+}
+
+func expandRoleSettingsNotifications(input []interface{}) []RoleSettingsNotification {
+	result := make([]RoleSettingsNotification, 0, len(input))
+	for _, item := range input {
+		notification := item.(map[string]interface{})
+		additionalRecipients := []string{}
+		for _, recipient := range notification["additional_recipients"].(*schema.Set).List() {
+			additionalRecipients = append(additionalRecipients, recipient.(string))
+		}
+		result = append(result, RoleSettingsNotification{
+			Type:                 notification["type"].(string),
+			RecipientType:        notification["recipient_type"].(string),
+			DefaultRecipients:    notification["default_recipients"].(bool),
+			AdditionalRecipients: additionalRecipients,
+			CriticalOnly:         notification["critical_only"].(bool),
+		})
+	}
+	return result
+}
+
+// filterRoleSettingsNotifications only returns the notifications matching a type and recipient type in
+// managed, so notifications left to PIM do not show up as drift.
+func filterRoleSettingsNotifications(notifications []RoleSettingsNotification, managed []interface{}) []RoleSettingsNotification {
+	result := make([]RoleSettingsNotification, 0, len(managed))
+	for _, item := range managed {
+		key := item.(map[string]interface{})
+		notification := findRoleSettingsNotification(notifications, key["type"].(string), key["recipient_type"].(string))
+		if notification != nil {
+			result = append(result, *notification)
+		}
+	}
+	return result
+}
+
+func flattenRoleSettingsNotifications(notifications []RoleSettingsNotification) []interface{} {
+	result := make([]interface{}, 0, len(notifications))
+	for _, notification := range notifications {
+		result = append(result, map[string]interface{}{
+			"type":                  notification.Type,
+			"recipient_type":        notification.RecipientType,
+			"default_recipients":    notification.DefaultRecipients,
+			"additional_recipients": notification.AdditionalRecipients,
+			"critical_only":         notification.CriticalOnly,
+		})
+	}
+	return result
+}
+
+func flattenRoleSettingsActivation(roleSettingsOptions *RoleSettingsOptions, maxDuration string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"max_duration_mins":      roleSettingsOptions.MaxActivationTimeMins,
+			"max_duration":           maxDuration,
+			"require_mfa":            roleSettingsOptions.RequireMFAOnActivation,
+			"authentication_context": roleSettingsOptions.ActivationAuthenticationContext,
+			"require_justification":  roleSettingsOptions.RequireJustificationOnActivation,
+			"require_ticket_info":    roleSettingsOptions.RequireTicketInfoOnActivation,
+			"require_approval":       roleSettingsOptions.RequireApprovalOnActivation,
+			"approver":               flattenRoleSettingsApprovers(roleSettingsOptions.Approvers),
+		},
+	}
+}
+
+func flattenRoleSettingsEligibleAssignment(roleSettingsOptions *RoleSettingsOptions, maxDuration string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"allow_permanent":   roleSettingsOptions.AllowPermanentEligibleAssignments,
+			"max_duration_mins": roleSettingsOptions.MaxEligibleAssignmentTimeMins,
+			"max_duration":      maxDuration,
+		},
+	}
+}
+
+func flattenRoleSettingsActiveAssignment(roleSettingsOptions *RoleSettingsOptions, maxDuration string) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"allow_permanent":       roleSettingsOptions.AllowPermanentActiveAssignments,
+			"max_duration_mins":     roleSettingsOptions.MaxActiveAssignmentTimeMins,
+			"max_duration":          maxDuration,
+			"require_mfa":           roleSettingsOptions.RequireMFAOnActiveAssignment,
+			"require_justification": roleSettingsOptions.RequireJustificationOnActiveAssignment,
+		},
+	}
+}
+
+// checkRoleSettingsAuthenticationContext rejects require_mfa = true together with an authentication context.
+// It looks at the configuration, since the planned require_mfa may still be the true read from PIM.
+func checkRoleSettingsAuthenticationContext(config cty.Value) error {
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	activation := config.GetAttr("activation")
+	if activation.IsNull() || !activation.IsKnown() || activation.LengthInt() == 0 {
+		return nil
+	}
+	block := activation.Index(cty.NumberIntVal(0))
+
+	requireMfa := block.GetAttr("require_mfa")
+	authenticationContext := block.GetAttr("authentication_context")
+	if requireMfa.IsNull() || !requireMfa.IsKnown() || requireMfa.False() {
+		return nil
+	}
+	if authenticationContext.IsNull() || !authenticationContext.IsKnown() || authenticationContext.AsString() == "" {
+		return nil
+	}
+	return errors.New("activation.0.require_mfa cannot be true when activation.0.authentication_context is set, the authentication context replaces the MFA rule")
+}
+
+// roleSettingsManagedRules lists the rules each lifecycle entry already gets from the typed blocks, keyed by
+// caller/level/operation. additional_rule must not overlay them, or the two would fight on every apply.
+var roleSettingsManagedRules = map[string][]string{
+	"Admin/Eligible/ALL": {"ExpirationRule", "NotificationRule"},
+	"Admin/Member/ALL":   {"ExpirationRule", "MfaRule", "JustificationRule", "NotificationRule"},
+	"EndUser/Member/ALL": {"ExpirationRule", "MfaRule", "AcrsRule", "JustificationRule", "TicketingRule", "ApprovalRule", "NotificationRule"},
+}
+
+func validateRoleSettingsAdditionalRules(d *schema.ResourceDiff) error {
+	for i, item := range d.Get("additional_rule").([]interface{}) {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		lifecycle := fmt.Sprintf("%s/%s/%s", rule["caller"], rule["level"], rule["operation"])
+		if containsString(roleSettingsManagedRules[lifecycle], rule["rule_identifier"].(string)) {
+			return fmt.Errorf("additional_rule.%d: %s of %s is managed by the activation, eligible_assignment, active_assignment and notification blocks", i, rule["rule_identifier"], lifecycle)
+		}
+	}
+	return nil
+}
+
+func expandRoleSettingsAdditionalRules(input []interface{}) ([]RoleSettingsAdditionalRule, error) {
+	result := make([]RoleSettingsAdditionalRule, 0, len(input))
+	for _, item := range input {
+		rule := item.(map[string]interface{})
+		setting, err := structure.ExpandJsonFromString(rule["setting"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("The setting of additional rule %s must be a JSON object: %s", rule["rule_identifier"], err)
+		}
+		result = append(result, RoleSettingsAdditionalRule{
+			Caller:         rule["caller"].(string),
+			Level:          rule["level"].(string),
+			Operation:      rule["operation"].(string),
+			RuleIdentifier: rule["rule_identifier"].(string),
+			Setting:        setting,
+		})
+	}
+	return result, nil
+}
+
+// flattenRoleSettingsAdditionalRules reads back the properties each configured rule manages. Rules missing
+// from the role are dropped, so the next apply adds them again.
+func flattenRoleSettingsAdditionalRules(roleSettings *azurepag.RoleSettings, configured []interface{}) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(configured))
+	for _, item := range configured {
+		rule := item.(map[string]interface{})
+
+		lifecycle := findLifecycleManagement(roleSettings, rule["caller"].(string), rule["level"].(string), rule["operation"].(string))
+		if lifecycle == nil {
+			continue
+		}
+
+		existing, err := getRuleSetting(lifecycle.RoleSettingsRules, rule["rule_identifier"].(string))
+		if err != nil {
+			continue
+		}
+
+		current := map[string]interface{}{}
+		err = json.Unmarshal([]byte(existing.Setting), &current)
+		if err != nil {
+			return nil, err
+		}
+
+		setting := map[string]interface{}{}
+		managed, err := structure.ExpandJsonFromString(rule["setting"].(string))
+		if err != nil {
+			return nil, err
+		}
+		for key := range managed {
+			setting[key] = current[key]
+		}
+
+		flattened, err := structure.FlattenJsonToString(setting)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, map[string]interface{}{
+			"caller":          rule["caller"],
+			"level":           rule["level"],
+			"operation":       rule["operation"],
+			"rule_identifier": rule["rule_identifier"],
+			"setting":         flattened,
+		})
+	}
+	return result, nil
+}
+
 func getActivationRules(roleSettings *azurepag.RoleSettings) (*azurepag.LifecycleManagement, error) {
 	for _, item := range roleSettings.LifecycleManagement {
 		if item.Caller == "EndUser" && item.Level == "Member" && item.Operation == "ALL" {
@@ -388,3 +1655,12 @@ func getEligibleAssignmentRules(roleSettings *azurepag.RoleSettings) (*azurepag.
 	}
 	return nil, errors.New("Role eligible assignment rules not found.")
 }
+
+func getActiveAssignmentRules(roleSettings *azurepag.RoleSettings) (*azurepag.LifecycleManagement, error) {
+	for _, item := range roleSettings.LifecycleManagement {
+		if item.Caller == "Admin" && item.Level == "Member" && item.Operation == "ALL" {
+			return &item, nil
+		}
+	}
+	return nil, errors.New("Role active assignment rules not found.")
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceRoleSettingsV0 is the flat schema used before the rules were grouped by lifecycle.
+func resourceRoleSettingsV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"role_definition_id":                         {Type: schema.TypeString, Computed: true},
+			"object_id":                                  {Type: schema.TypeString, Required: true, ForceNew: true},
+			"role_name":                                  {Type: schema.TypeString, Required: true, ForceNew: true},
+			"allow_permanent_eligible_assignments":       {Type: schema.TypeBool, Optional: true, Computed: true},
+			"max_eligible_assignment_time_mins":          {Type: schema.TypeInt, Optional: true, Computed: true},
+			"max_activation_time_mins":                   {Type: schema.TypeInt, Optional: true, Computed: true},
+			"require_mfa_on_activation":                  {Type: schema.TypeBool, Optional: true, Computed: true},
+			"activation_authentication_context":          {Type: schema.TypeString, Optional: true, Computed: true},
+			"require_justification_on_activation":        {Type: schema.TypeBool, Optional: true, Computed: true},
+			"require_ticket_info_on_activation":          {Type: schema.TypeBool, Optional: true, Computed: true},
+			"allow_permanent_active_assignments":         {Type: schema.TypeBool, Optional: true, Computed: true},
+			"max_active_assignment_time_mins":            {Type: schema.TypeInt, Optional: true, Computed: true},
+			"require_mfa_on_active_assignment":           {Type: schema.TypeBool, Optional: true, Computed: true},
+			"require_justification_on_active_assignment": {Type: schema.TypeBool, Optional: true, Computed: true},
+			"require_approval_on_activation":             {Type: schema.TypeBool, Optional: true, Computed: true},
+			"approver": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_id": {Type: schema.TypeString, Required: true},
+						"type":      {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			"notification": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":                  {Type: schema.TypeString, Required: true},
+						"recipient_type":        {Type: schema.TypeString, Required: true},
+						"default_recipients":    {Type: schema.TypeBool, Optional: true, Default: true},
+						"additional_recipients": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"critical_only":         {Type: schema.TypeBool, Optional: true, Default: false},
+					},
+				},
+			},
+			"restore_on_destroy":     {Type: schema.TypeBool, Optional: true, Default: false},
+			"reset_to_defaults":      {Type: schema.TypeBool, Optional: true, Default: false},
+			"original_settings_json": {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// roleSettingsV0Blocks maps each nested block to the flat attributes it replaces.
+var roleSettingsV0Blocks = map[string]map[string]string{
+	"activation": {
+		"max_duration_mins":      "max_activation_time_mins",
+		"require_mfa":            "require_mfa_on_activation",
+		"authentication_context": "activation_authentication_context",
+		"require_justification":  "require_justification_on_activation",
+		"require_ticket_info":    "require_ticket_info_on_activation",
+		"require_approval":       "require_approval_on_activation",
+		"approver":               "approver",
+	},
+	"eligible_assignment": {
+		"allow_permanent":   "allow_permanent_eligible_assignments",
+		"max_duration_mins": "max_eligible_assignment_time_mins",
+	},
+	"active_assignment": {
+		"allow_permanent":       "allow_permanent_active_assignments",
+		"max_duration_mins":     "max_active_assignment_time_mins",
+		"require_mfa":           "require_mfa_on_active_assignment",
+		"require_justification": "require_justification_on_active_assignment",
+	},
+}
+
+// resourceRoleSettingsStateUpgradeV0 moves the flat rule attributes into the activation, eligible_assignment
+// and active_assignment blocks. Attributes missing from old state are left for the next read to fill in.
+func resourceRoleSettingsStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for block, attributes := range roleSettingsV0Blocks {
+		values := map[string]interface{}{}
+		for attribute, flatAttribute := range attributes {
+			if value, ok := rawState[flatAttribute]; ok {
+				values[attribute] = value
+				delete(rawState, flatAttribute)
+			}
+		}
+		if len(values) > 0 {
+			rawState[block] = []interface{}{values}
+		}
+	}
+	return rawState, nil
+}
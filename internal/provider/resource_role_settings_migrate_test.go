@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceRoleSettingsStateUpgradeV0(t *testing.T) {
+	approvers := []interface{}{
+		map[string]interface{}{"object_id": "5c4d3e2f-1a0b-4c9d-8e7f-6a5b4c3d2e1f", "type": "User"},
+	}
+	rawState := map[string]interface{}{
+		"id":                                   "b9b5d1a4-6c3e-4f7a-9d2b-0c1e2f3a4b5c",
+		"object_id":                            "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name":                            "Owner",
+		"allow_permanent_eligible_assignments": false,
+		"max_eligible_assignment_time_mins":    float64(43200),
+		"max_activation_time_mins":             float64(90),
+		"require_mfa_on_activation":            true,
+		"require_justification_on_activation":  true,
+		"require_ticket_info_on_activation":    false,
+		"require_approval_on_activation":       true,
+		"approver":                             approvers,
+		"restore_on_destroy":                   true,
+	}
+
+	actual, err := resourceRoleSettingsStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":        "b9b5d1a4-6c3e-4f7a-9d2b-0c1e2f3a4b5c",
+		"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name": "Owner",
+		"activation": []interface{}{
+			map[string]interface{}{
+				"max_duration_mins":     float64(90),
+				"require_mfa":           true,
+				"require_justification": true,
+				"require_ticket_info":   false,
+				"require_approval":      true,
+				"approver":              approvers,
+			},
+		},
+		"eligible_assignment": []interface{}{
+			map[string]interface{}{
+				"allow_permanent":   false,
+				"max_duration_mins": float64(43200),
+			},
+		},
+		"restore_on_destroy": true,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+}
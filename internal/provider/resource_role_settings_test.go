@@ -0,0 +1,587 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/oskarm93/azurepag-client-go"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestMergeRoleSettings(t *testing.T) {
+	roleSettings := readRoleSettingsFixture(t, "testdata/role_settings.json")
+
+	merged, err := mergeRoleSettings(roleSettings, &RoleSettingsOptions{
+		AllowPermanentEligibleAssignments: false,
+		MaxEligibleAssignmentTimeMins:     43200,
+		MaxActivationTimeMins:             90,
+		RequireMFAOnActivation:            true,
+		RequireJustificationOnActivation:  true,
+		RequireTicketInfoOnActivation:     true,
+		RequireApprovalOnActivation:       true,
+		Approvers: []RoleSettingsApprover{
+			{ID: "5c4d3e2f-1a0b-4c9d-8e7f-6a5b4c3d2e1f", Type: "User"},
+		},
+		AllowPermanentActiveAssignments:        false,
+		MaxActiveAssignmentTimeMins:            20160,
+		RequireMFAOnActiveAssignment:           true,
+		RequireJustificationOnActiveAssignment: true,
+		Notifications: []RoleSettingsNotification{
+			{Type: "Activation", RecipientType: "Admin", DefaultRecipients: true, AdditionalRecipients: []string{"soc@example.com"}, CriticalOnly: false},
+			{Type: "Activation", RecipientType: "Approver", DefaultRecipients: false, AdditionalRecipients: []string{}, CriticalOnly: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertGolden(t, "testdata/role_settings_merged.golden.json", merged)
+
+	roleSettingsOptions, err := getRoleSettingsOptions(merged)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	notification := findRoleSettingsNotification(roleSettingsOptions.Notifications, "Activation", "Admin")
+	if notification == nil || !reflect.DeepEqual(notification.AdditionalRecipients, []string{"soc@example.com"}) || notification.CriticalOnly {
+		t.Errorf("Activation Admin notification did not round-trip, got %+v", notification)
+	}
+	notification = findRoleSettingsNotification(roleSettingsOptions.Notifications, "Activation", "Approver")
+	if notification == nil || notification.DefaultRecipients || !notification.CriticalOnly {
+		t.Errorf("Activation Approver notification did not round-trip, got %+v", notification)
+	}
+
+	// Rules the provider does not manage must pass through untouched.
+	unmanaged := map[string][]string{
+		"Admin/Eligible":   {"MfaRule", "JustificationRule", "AttributeConditionRule"},
+		"EndUser/Eligible": {"NotificationRule"},
+	}
+	for key, ruleIdentifiers := range unmanaged {
+		for _, ruleIdentifier := range ruleIdentifiers {
+			expected := findRuleFixture(t, roleSettings, key, ruleIdentifier)
+			actual := findRuleFixture(t, merged, key, ruleIdentifier)
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("%s %s changed: expected %s, got %s", key, ruleIdentifier, expected.Setting, actual.Setting)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(roleSettings, readRoleSettingsFixture(t, "testdata/role_settings.json")) {
+		t.Error("mergeRoleSettings modified its input")
+	}
+}
+
+func TestMergeRoleSettingsAddsMissingRules(t *testing.T) {
+	merged, err := mergeRoleSettings(&azurepag.RoleSettings{ID: "settings"}, &defaultRoleSettingsOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	roleSettingsOptions, err := getRoleSettingsOptions(merged)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(*roleSettingsOptions, defaultRoleSettingsOptions) {
+		t.Errorf("expected %+v, got %+v", defaultRoleSettingsOptions, *roleSettingsOptions)
+	}
+}
+
+func TestMergeRoleSettingsAuthenticationContext(t *testing.T) {
+	roleSettingsOptions := defaultRoleSettingsOptions
+	roleSettingsOptions.RequireMFAOnActivation = true
+	roleSettingsOptions.ActivationAuthenticationContext = "c1"
+
+	merged, err := mergeRoleSettings(readRoleSettingsFixture(t, "testdata/role_settings.json"), &roleSettingsOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if rule := findRuleFixture(t, merged, "EndUser/Member", "AcrsRule"); rule.Setting != `{"acrs":"c1","acrsRequired":true}` {
+		t.Errorf("unexpected AcrsRule setting %s", rule.Setting)
+	}
+
+	actual, err := getRoleSettingsOptions(merged)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual.ActivationAuthenticationContext != "c1" {
+		t.Errorf("expected authentication context c1, got %q", actual.ActivationAuthenticationContext)
+	}
+	if actual.RequireMFAOnActivation {
+		t.Error("expected the MFA rule to be turned off by the authentication context")
+	}
+}
+
+func TestCheckRoleSettingsDuration(t *testing.T) {
+	activation := roleSettingsDurationLimits[0]
+	eligibleAssignment := roleSettingsDurationLimits[1]
+
+	valid := map[time.Duration]roleSettingsDurationLimit{
+		30 * time.Minute:     activation,
+		90 * time.Minute:     activation,
+		24 * time.Hour:       activation,
+		24 * time.Hour * 365: eligibleAssignment,
+		24 * time.Hour * 15:  eligibleAssignment,
+	}
+	for duration, limit := range valid {
+		if err := checkRoleSettingsDuration("max_duration", duration, limit); err != nil {
+			t.Errorf("%s %s: unexpected error: %s", limit.Block, duration, err)
+		}
+	}
+
+	invalid := map[time.Duration]roleSettingsDurationLimit{
+		15 * time.Minute:     activation,
+		45 * time.Minute:     activation,
+		25 * time.Hour:       activation,
+		36 * time.Hour:       eligibleAssignment,
+		24 * time.Hour * 366: eligibleAssignment,
+	}
+	for duration, limit := range invalid {
+		if err := checkRoleSettingsDuration("max_duration", duration, limit); err == nil {
+			t.Errorf("%s %s: expected an error", limit.Block, duration)
+		}
+	}
+}
+
+func TestFlattenRoleSettingsDuration(t *testing.T) {
+	cases := []struct {
+		current  string
+		minutes  int
+		expected string
+	}{
+		{"", 480, ""},
+		{"8h", 480, "8h"},
+		{"PT8H", 480, "PT8H"},
+		{"P365D", 525600, "P365D"},
+		{"8h", 240, "4h0m0s"},
+	}
+	for _, c := range cases {
+		if actual := flattenRoleSettingsDuration(c.current, c.minutes); actual != c.expected {
+			t.Errorf("%q with %d minutes: expected %q, got %q", c.current, c.minutes, c.expected, actual)
+		}
+	}
+}
+
+func TestSetNotificationRuleKeepsUnknownProperties(t *testing.T) {
+	roleSettings := &azurepag.RoleSettings{
+		ID: "settings",
+		LifecycleManagement: []azurepag.LifecycleManagement{
+			{
+				Caller:    "EndUser",
+				Level:     "Member",
+				Operation: "ALL",
+				RoleSettingsRules: []azurepag.RoleSettingsRule{
+					{
+						RuleIdentifier: "NotificationRule",
+						Setting:        `{"policies":[{"deliveryMechanism":"email","enabled":true,"setting":[{"customreceivers":null,"isdefaultreceiverenabled":true,"notificationlevel":2,"recipienttype":0,"sendToOwners":true},{"customreceivers":null,"isdefaultreceiverenabled":true,"notificationlevel":2,"recipienttype":2}]},{"deliveryMechanism":"teams","setting":[]}],"version":2}`,
+					},
+				},
+			},
+		},
+	}
+
+	err := setNotificationRule(roleSettings, "Activation", []RoleSettingsNotification{
+		{Type: "Activation", RecipientType: "Admin", DefaultRecipients: false, AdditionalRecipients: []string{"soc@example.com"}, CriticalOnly: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `{"policies":[{"deliveryMechanism":"email","enabled":true,"setting":[{"customreceivers":["soc@example.com"],"isdefaultreceiverenabled":false,"notificationlevel":1,"recipienttype":0,"sendToOwners":true},{"customreceivers":null,"isdefaultreceiverenabled":true,"notificationlevel":2,"recipienttype":2}]},{"deliveryMechanism":"teams","setting":[]}],"version":2}`
+	if rule := findRuleFixture(t, roleSettings, "EndUser/Member", "NotificationRule"); rule.Setting != expected {
+		t.Errorf("expected %s, got %s", expected, rule.Setting)
+	}
+}
+
+func TestCheckRoleSettingsAuthenticationContext(t *testing.T) {
+	config := func(requireMfa cty.Value, authenticationContext cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"activation": cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"require_mfa":            requireMfa,
+					"authentication_context": authenticationContext,
+				}),
+			}),
+		})
+	}
+
+	cases := []struct {
+		name    string
+		config  cty.Value
+		invalid bool
+	}{
+		{"both off", config(cty.False, cty.StringVal("")), false},
+		{"both set", config(cty.True, cty.StringVal("c1")), true},
+		{"mfa off with context", config(cty.False, cty.StringVal("c1")), false},
+		{"mfa without context", config(cty.True, cty.StringVal("")), false},
+		{"context only", config(cty.NullVal(cty.Bool), cty.StringVal("c1")), false},
+		{"unknown context", config(cty.True, cty.UnknownVal(cty.String)), false},
+		{"no activation", cty.ObjectVal(map[string]cty.Value{"activation": cty.ListValEmpty(cty.EmptyObject)}), false},
+		{"no config", cty.NullVal(cty.DynamicPseudoType), false},
+	}
+	for _, c := range cases {
+		err := checkRoleSettingsAuthenticationContext(c.config)
+		if c.invalid && err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		} else if !c.invalid && err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+		}
+	}
+}
+
+func TestRoleSettingsAdditionalRules(t *testing.T) {
+	roleSettings := readRoleSettingsFixture(t, "testdata/role_settings.json")
+	configured := []interface{}{
+		map[string]interface{}{
+			"caller":          "Admin",
+			"level":           "Eligible",
+			"operation":       "ALL",
+			"rule_identifier": "AttributeConditionRule",
+			"setting":         `{"enableEnforcement": true}`,
+		},
+		map[string]interface{}{
+			"caller":          "EndUser",
+			"level":           "Member",
+			"operation":       "ALL",
+			"rule_identifier": "MissingRule",
+			"setting":         `{"enabled":true}`,
+		},
+	}
+
+	additionalRules, err := expandRoleSettingsAdditionalRules(configured[:1])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	roleSettingsOptions, err := getRoleSettingsOptions(roleSettings)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	roleSettingsOptions.AdditionalRules = additionalRules
+
+	merged, err := mergeRoleSettings(roleSettings, roleSettingsOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `{"condition":null,"conditionDescription":null,"conditionVersion":null,"enableEnforcement":true}`
+	if rule := findRuleFixture(t, merged, "Admin/Eligible", "AttributeConditionRule"); rule.Setting != expected {
+		t.Errorf("expected AttributeConditionRule setting %s, got %s", expected, rule.Setting)
+	}
+
+	flattened, err := flattenRoleSettingsAdditionalRules(merged, configured)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(flattened) != 1 {
+		t.Fatalf("expected the missing rule to be dropped, got %d rules", len(flattened))
+	}
+	if setting := flattened[0].(map[string]interface{})["setting"]; setting != `{"enableEnforcement":true}` {
+		t.Errorf("expected only the managed properties to be read back, got %s", setting)
+	}
+
+	if _, err := expandRoleSettingsAdditionalRules([]interface{}{
+		map[string]interface{}{"caller": "Admin", "level": "Member", "operation": "ALL", "rule_identifier": "MfaRule", "setting": `[true]`},
+	}); err == nil {
+		t.Error("non-object setting: expected an error")
+	}
+}
+
+func TestResourceRoleSettingsImport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRoleSettings().Schema, map[string]interface{}{})
+	d.SetId("3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c/owner")
+
+	if _, err := resourceRoleSettingsImport(context.Background(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if objectId := d.Get("object_id").(string); objectId != "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c" {
+		t.Errorf("unexpected object_id %q", objectId)
+	}
+	if roleName := d.Get("role_name").(string); roleName != "Owner" {
+		t.Errorf("unexpected role_name %q", roleName)
+	}
+
+	for _, id := range []string{"3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "not-a-uuid/Owner", "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c/Admin", "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c/Owner/Eligible"} {
+		d.SetId(id)
+		if _, err := resourceRoleSettingsImport(context.Background(), d, nil); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
+
+func TestResourceRoleSettingsCustomizeDiff(t *testing.T) {
+	config := func(blocks map[string]interface{}) map[string]interface{} {
+		result := map[string]interface{}{
+			"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+			"role_name": "Owner",
+		}
+		for key, value := range blocks {
+			result[key] = []interface{}{value}
+		}
+		return result
+	}
+	approver := []interface{}{
+		map[string]interface{}{"object_id": "5c4d3e2f-1a0b-4c9d-8e7f-6a5b4c3d2e1f", "type": "User"},
+	}
+	existing := map[string]string{
+		"id":                                    "settings",
+		"object_id":                             "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name":                             "Owner",
+		"eligible_assignment.#":                 "1",
+		"eligible_assignment.0.allow_permanent": "true",
+		"eligible_assignment.0.max_duration_mins": "43200",
+	}
+
+	cases := []struct {
+		name    string
+		state   map[string]string
+		config  map[string]interface{}
+		invalid bool
+	}{
+		{"no blocks", nil, config(nil), false},
+		{"non-permanent without maximum", nil, config(map[string]interface{}{"eligible_assignment": map[string]interface{}{"allow_permanent": false}}), true},
+		{"non-permanent with maximum", nil, config(map[string]interface{}{"eligible_assignment": map[string]interface{}{"allow_permanent": false, "max_duration": "P30D"}}), false},
+		{"non-permanent active without maximum", nil, config(map[string]interface{}{"active_assignment": map[string]interface{}{"allow_permanent": false}}), true},
+		{"other active assignment rules", nil, config(map[string]interface{}{"active_assignment": map[string]interface{}{"require_mfa": true}}), false},
+		{"restore without snapshot", existing, map[string]interface{}{"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "role_name": "Owner", "restore_on_destroy": true}, true},
+		{"restore on new settings", nil, map[string]interface{}{"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "role_name": "Owner", "restore_on_destroy": true}, false},
+		{"non-permanent with existing maximum", existing, config(map[string]interface{}{"eligible_assignment": map[string]interface{}{"allow_permanent": false}}), false},
+		{"approval without approvers", nil, config(map[string]interface{}{"activation": map[string]interface{}{"require_approval": true}}), true},
+		{"approval with approvers", nil, config(map[string]interface{}{"activation": map[string]interface{}{"require_approval": true, "approver": approver}}), false},
+		{"activation too long", nil, config(map[string]interface{}{"activation": map[string]interface{}{"max_duration": "25h"}}), true},
+		{"activation off step", nil, config(map[string]interface{}{"activation": map[string]interface{}{"max_duration_mins": 45}}), true},
+		{"activation within limits", nil, config(map[string]interface{}{"activation": map[string]interface{}{"max_duration": "PT8H"}}), false},
+		{"additional rule", nil, config(map[string]interface{}{"additional_rule": map[string]interface{}{"caller": "EndUser", "level": "Member", "rule_identifier": "AttributeConditionRule", "setting": `{"isEnabled":false}`}}), false},
+		{"additional rule managed by a block", nil, config(map[string]interface{}{"additional_rule": map[string]interface{}{"caller": "EndUser", "level": "Member", "rule_identifier": "ApprovalRule", "setting": `{"enabled":true}`}}), true},
+		{"additional rule on another operation", nil, config(map[string]interface{}{"additional_rule": map[string]interface{}{"caller": "Admin", "level": "Eligible", "operation": "Renew", "rule_identifier": "ExpirationRule", "setting": `{"maximumGrantPeriodInMinutes":60}`}}), false},
+	}
+	for _, c := range cases {
+		var state *terraform.InstanceState
+		if c.state != nil {
+			state = &terraform.InstanceState{ID: c.state["id"], Attributes: c.state}
+		}
+
+		_, err := resourceRoleSettings().Diff(context.Background(), state, terraform.NewResourceConfigRaw(c.config), nil)
+		if c.invalid && err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		} else if !c.invalid && err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+		}
+	}
+}
+
+func TestResourceRoleSettingsPlannedPayload(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "settings",
+		Attributes: map[string]string{
+			"id":                     "settings",
+			"object_id":              "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+			"role_name":              "Owner",
+			"restore_on_destroy":     "false",
+			"reset_to_defaults":      "false",
+			"raw_settings_json":      `{"id":"settings"}`,
+			"planned_payload_json":   `{"id":"settings"}`,
+			"original_settings_json": `{"id":"settings"}`,
+		},
+	}
+
+	unchanged, err := resourceRoleSettings().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"object_id":          "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name":          "Owner",
+		"restore_on_destroy": true,
+	}), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if unchanged.Attributes["raw_settings_json"] != nil || unchanged.Attributes["planned_payload_json"] != nil {
+		t.Errorf("expected the settings documents to stay known when only restore_on_destroy changes, got %v", unchanged.Attributes)
+	}
+
+	changed, err := resourceRoleSettings().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"object_id":  "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name":  "Owner",
+		"activation": []interface{}{map[string]interface{}{"max_duration": "PT4H"}},
+	}), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, key := range []string{"raw_settings_json", "planned_payload_json"} {
+		if attribute := changed.Attributes[key]; attribute == nil || !attribute.NewComputed {
+			t.Errorf("expected %s to be unknown when activation changes, got %+v", key, attribute)
+		}
+	}
+
+	fixture, err := ioutil.ReadFile("testdata/role_settings.json")
+	if err != nil {
+		t.Fatalf("reading fixture: %s", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/roleDefinitions"):
+			w.Write([]byte(`{"value":[]}`))
+		case strings.HasSuffix(r.URL.Path, "/roleSettingsv2"):
+			fmt.Fprintf(w, `{"value":[%s]}`, fixture)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := &Client{Client: &azurepag.Client{BaseURL: server.URL, HTTPClient: server.Client()}}
+
+	// The group is not registered yet, so the payload is left for apply to build.
+	unregistered, err := resourceRoleSettings().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name": "Owner",
+	}), client)
+	if err != nil {
+		t.Fatalf("unregistered group: unexpected error: %s", err)
+	}
+	if attribute := unregistered.Attributes["planned_payload_json"]; attribute == nil || !attribute.NewComputed {
+		t.Errorf("unregistered group: expected planned_payload_json to be unknown, got %+v", attribute)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceRoleSettings().Schema, map[string]interface{}{})
+	live, err := getPlannedRoleSettings(client, d, "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "definition")
+	if err != nil {
+		t.Fatalf("no planned payload: unexpected error: %s", err)
+	}
+	payload, err := json.Marshal(live)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	d.Set("planned_payload_json", string(payload))
+	if _, err := getPlannedRoleSettings(client, d, "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "definition"); err != nil {
+		t.Errorf("unchanged settings: unexpected error: %s", err)
+	}
+
+	d.Set("planned_payload_json", `{"id":"settings","lifeCycleManagement":[]}`)
+	if _, err := getPlannedRoleSettings(client, d, "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c", "definition"); err == nil {
+		t.Error("settings changed since plan: expected an error")
+	}
+}
+
+func TestResourceRoleSettingsMaxDurationChange(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "settings",
+		Attributes: map[string]string{
+			"id":                             "settings",
+			"object_id":                      "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+			"role_name":                      "Owner",
+			"restore_on_destroy":             "false",
+			"reset_to_defaults":              "false",
+			"activation.#":                   "1",
+			"activation.0.max_duration":      "8h",
+			"activation.0.max_duration_mins": "480",
+			"activation.0.require_mfa":       "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"object_id": "3f2a1b0c-9d8e-4f7a-8b6c-5d4e3f2a1b0c",
+		"role_name": "Owner",
+		"activation": []interface{}{
+			map[string]interface{}{"max_duration": "4h", "require_mfa": true},
+		},
+	})
+
+	// Capture what the changed max_duration turns into, as the planned payload would.
+	var roleSettingsOptions *RoleSettingsOptions
+	resource := resourceRoleSettings()
+	customizeDiff := resource.CustomizeDiff
+	resource.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		err := customizeDiff(ctx, d, meta)
+		if err != nil {
+			return err
+		}
+
+		updatedRoleSettings, err := createUpdatedRoleSettings(readRoleSettingsFixture(t, "testdata/role_settings.json"), d)
+		if err != nil {
+			return err
+		}
+		roleSettingsOptions, err = getRoleSettingsOptions(updatedRoleSettings)
+		return err
+	}
+
+	diff, err := resource.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attribute := diff.Attributes["activation.0.max_duration_mins"]; attribute == nil || attribute.New != "240" {
+		t.Errorf("expected activation.0.max_duration_mins to be planned as 240, got %+v", attribute)
+	}
+	if roleSettingsOptions == nil || roleSettingsOptions.MaxActivationTimeMins != 240 {
+		t.Fatalf("expected a maximum activation of 240 minutes, got %+v", roleSettingsOptions)
+	}
+	if attribute := diff.Attributes["activation.0.require_mfa"]; attribute != nil && attribute.New != "true" {
+		t.Errorf("expected activation.0.require_mfa to be kept, got %+v", attribute)
+	}
+}
+
+func readRoleSettingsFixture(t *testing.T, path string) *azurepag.RoleSettings {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %s", path, err)
+	}
+
+	roleSettings := azurepag.RoleSettings{}
+	if err := json.Unmarshal(data, &roleSettings); err != nil {
+		t.Fatalf("parsing %s: %s", path, err)
+	}
+	return &roleSettings
+}
+
+func findRuleFixture(t *testing.T, roleSettings *azurepag.RoleSettings, key string, ruleIdentifier string) azurepag.RoleSettingsRule {
+	t.Helper()
+
+	for _, lifecycle := range roleSettings.LifecycleManagement {
+		if lifecycle.Caller+"/"+lifecycle.Level != key {
+			continue
+		}
+		for _, rule := range lifecycle.RoleSettingsRules {
+			if rule.RuleIdentifier == ruleIdentifier {
+				return rule
+			}
+		}
+	}
+	t.Fatalf("%s %s not found", key, ruleIdentifier)
+	return azurepag.RoleSettingsRule{}
+}
+
+func assertGolden(t *testing.T, path string, value interface{}) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	actual = append(actual, '\n')
+
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("writing %s: %s", path, err)
+		}
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %s", path, err)
+	}
+	if string(expected) != string(actual) {
+		t.Errorf("%s does not match, run go test with -update to regenerate it\n\ngot:\n%s", path, actual)
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var assignmentStates = []string{"Eligible", "Active"}
+
+var roleNames = []string{"Owner", "Member"}
+
+var validateAssignmentState = validation.StringInSlice(assignmentStates, true)
+
+var validateRoleName = validation.StringInSlice(roleNames, true)
+
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// canonicalCase stores a case-insensitive value with the casing the PIM API expects.
+func canonicalCase(values []string) schema.SchemaStateFunc {
+	return func(v interface{}) string {
+		for _, item := range values {
+			if strings.EqualFold(item, v.(string)) {
+				return item
+			}
+		}
+		return v.(string)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	return indexOfString(values, value) >= 0
+}
+
+func indexOfString(values []string, value string) int {
+	for i, item := range values {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,30 @@
+package provider
+
+import "testing"
+
+func TestValidateAssignmentState(t *testing.T) {
+	for _, v := range []string{"Eligible", "eligible", "ACTIVE"} {
+		if _, errs := validateAssignmentState(v, "assignment_state"); len(errs) > 0 {
+			t.Errorf("%s: unexpected errors: %v", v, errs)
+		}
+	}
+	for _, v := range []string{"", "Eligable", "Permanent"} {
+		if _, errs := validateAssignmentState(v, "assignment_state"); len(errs) == 0 {
+			t.Errorf("%s: expected an error", v)
+		}
+	}
+}
+
+func TestCanonicalCase(t *testing.T) {
+	stateFunc := canonicalCase(roleNames)
+	cases := map[string]string{
+		"member":  "Member",
+		"OWNER":   "Owner",
+		"Members": "Members",
+	}
+	for v, expected := range cases {
+		if actual := stateFunc(v); actual != expected {
+			t.Errorf("%s: expected %s, got %s", v, expected, actual)
+		}
+	}
+}